@@ -264,7 +264,7 @@ provider "" {
 #     value = ["1.1.1.1"]
 #   }
 
-#   threshold = {
+#   threshold {
 # 		period = 30
 # 		operator = "gt"
 # 		count = 30
@@ -275,8 +275,9 @@ provider "" {
 #   }
 
 #   actions {
-#     action_id = "block_ips"
-#     lock_time = 60
+#     action_id        = "block_ips"
+#     lock_time        = 60
+#     lock_time_format = "Seconds"
 #   }
 
 # }
@@ -27,4 +27,12 @@ resource "wallarm_denylist" "denylist_datacenter" {
   reason      = "TEST DENYLIST DATACENTER"
   time_format = "Minutes"
   time        = 60
-}
\ No newline at end of file
+}
+
+resource "wallarm_denylist" "denylist_asn" {
+  application = [0] # All Applications
+  asn         = ["AS64496", "64511"]
+  reason      = "TEST DENYLIST ASN"
+  time_format = "Days"
+  time        = 30
+}
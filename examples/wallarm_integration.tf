@@ -0,0 +1,34 @@
+resource "wallarm_integration" "fluentd" {
+  name   = "Terraform Fluentd Integration"
+  type   = "fluentd"
+  active = true
+
+  config = {
+    url         = "https://fluentd.example.com:9880"
+    http_method = "POST"
+    format      = "json"
+  }
+  config_json = jsonencode({
+    ca_verify    = true
+    timeout      = 15
+    open_timeout = 20
+  })
+
+  event {
+    event_type = "siem"
+    active     = true
+  }
+  event {
+    event_type = "system"
+    active     = true
+  }
+}
+
+resource "wallarm_integration" "pager_duty" {
+  type   = "pager_duty"
+  target = "0123456789abcdef0123456789abcdef"
+
+  event {
+    event_type = "security_issue_critical"
+  }
+}
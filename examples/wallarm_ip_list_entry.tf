@@ -0,0 +1,24 @@
+resource "wallarm_ip_list_entry" "office" {
+  list_type   = "allowlist"
+  value       = "203.0.113.0/24"
+  reason      = "Office egress (team: platform)"
+  time_format = "Forever"
+}
+
+resource "wallarm_ip_list_entry" "scanner" {
+  list_type   = "denylist"
+  value       = "198.51.100.7"
+  application = [1, 3]
+  reason      = "Scanner (team: appsec)"
+  time_format = "Days"
+  time        = 7
+  renew       = "never"
+}
+
+resource "wallarm_ip_list_entry" "tor" {
+  list_type   = "graylist"
+  rule_type   = "proxy_type"
+  value       = "TOR"
+  reason      = "TOR exit nodes"
+  time_format = "Forever"
+}
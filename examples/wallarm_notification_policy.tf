@@ -0,0 +1,22 @@
+resource "wallarm_integration_pagerduty" "oncall" {
+  name            = "Terraform On-call"
+  integration_key = "eb7ddfc33acaaacaacaca55a39834dad"
+  active          = true
+}
+
+resource "wallarm_notification_policy" "critical_attacks" {
+  name      = "Critical attacks"
+  templates = ["attacks_exceeded", "incidents_exceeded", "vector_attack"]
+
+  integration_ids = [wallarm_integration_pagerduty.oncall.integration_id]
+
+  block_ips {
+    lock_time        = 1
+    lock_time_format = "Hours"
+  }
+
+  threshold {
+    count  = 100
+    period = 3600
+  }
+}
@@ -34,7 +34,7 @@ resource "wallarm_trigger" "attack_trigger" {
     value     = [wallarm_application.tf_app.app_id]
   }
 
-  threshold = {
+  threshold {
     period   = 86400
     operator = "gt"
     count    = 10000
@@ -73,7 +73,7 @@ resource "wallarm_trigger" "vector_trigger" {
     value     = ["2.2.2.2"]
   }
 
-  threshold = {
+  threshold {
     operator = "gt"
     count    = 5
     period   = 3600
@@ -118,11 +118,12 @@ resource "wallarm_trigger" "brute_trigger" {
   }
 
   actions {
-    action_id = "block_ips"
-    lock_time = 2592000
+    action_id        = "block_ips"
+    lock_time        = 2592000
+    lock_time_format = "Seconds"
   }
 
-  threshold = {
+  threshold {
     period   = 30
     operator = "gt"
     count    = 30
@@ -162,11 +163,12 @@ resource "wallarm_trigger" "bola_trigger" {
   }
 
   actions {
-    action_id = "block_ips"
-    lock_time = 2592000
+    action_id        = "block_ips"
+    lock_time        = 2592000
+    lock_time_format = "Seconds"
   }
 
-  threshold = {
+  threshold {
     period   = 30
     operator = "gt"
     count    = 30
@@ -215,15 +217,16 @@ resource "wallarm_trigger" "vector_attack_trigger" {
     value     = [wallarm_application.tf_app.app_id]
   }
 
-  threshold = {
+  threshold {
     period   = 86400
     operator = "gt"
     count    = 10000
   }
 
   actions {
-    action_id = "block_ips"
-    lock_time = 2592000
+    action_id        = "block_ips"
+    lock_time        = 2592000
+    lock_time_format = "Seconds"
   }
 
   depends_on = [
@@ -274,7 +277,7 @@ resource "wallarm_trigger" "attacks_exceeded_trigger" {
     value     = [wallarm_application.tf_app.app_id]
   }
 
-  threshold = {
+  threshold {
     period   = 86400
     operator = "gt"
     count    = 10000
@@ -327,7 +330,7 @@ resource "wallarm_trigger" "hits_exceeded_trigger" {
     value     = [wallarm_application.tf_app.app_id]
   }
 
-  threshold = {
+  threshold {
     period   = 86400
     operator = "gt"
     count    = 10000
@@ -380,7 +383,7 @@ resource "wallarm_trigger" "incidents_exceeded_trigger" {
     value     = [wallarm_application.tf_app.app_id]
   }
 
-  threshold = {
+  threshold {
     period   = 86400
     operator = "gt"
     count    = 10000
@@ -512,7 +515,7 @@ resource "wallarm_trigger" "attack_ip_grouping_trigger" {
   enabled     = false
   template_id = "attack_ip_grouping"
 
-  threshold = {
+  threshold {
     period   = 86400
     operator = "gt"
     count    = 10000
@@ -33,4 +33,5 @@ func main() {
 	}
 
 	plugin.Serve(opts)
+	wallarm.LogRunStats()
 }
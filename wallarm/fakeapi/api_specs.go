@@ -0,0 +1,201 @@
+package fakeapi
+
+import (
+	"net/http"
+	"time"
+)
+
+func (s *Server) apiSpecRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v4/clients/{client}/rules/api-specs", s.apiSpecList)
+	mux.HandleFunc("POST /v4/clients/{client}/rules/api-specs", s.apiSpecCreate)
+	mux.HandleFunc("GET /v4/clients/{client}/rules/api-specs/{id}", s.apiSpecRead)
+	mux.HandleFunc("PUT /v4/clients/{client}/rules/api-specs/{id}", s.apiSpecUpdate)
+	mux.HandleFunc("DELETE /v4/clients/{client}/rules/api-specs/{id}", s.apiSpecDelete)
+	mux.HandleFunc("PUT /v4/clients/{client}/rules/api-specs/{id}/policy", s.apiSpecPolicy)
+
+	mux.HandleFunc("GET /v1/clients/{client}/apid/config", s.discoveryRead)
+	mux.HandleFunc("POST /v1/clients/{client}/apid/config", s.discoveryUpdate)
+}
+
+// apiSpec returns the spec of the client in the path, writing a 404 if there
+// is none.
+func (s *Server) apiSpec(w http.ResponseWriter, r *http.Request) map[string]any {
+	clientID, ok := pathInt(w, r, "client")
+	if !ok {
+		return nil
+	}
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return nil
+	}
+	spec, ok := s.apiSpecs[id]
+	if !ok || toInt(spec["client_id"]) != clientID {
+		writeError(w, http.StatusNotFound, "Not found")
+		return nil
+	}
+	return spec
+}
+
+func (s *Server) apiSpecList(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathInt(w, r, "client")
+	if !ok {
+		return
+	}
+	pageNum, perPage := max(queryInt(r, "page"), 1), queryInt(r, "per_page")
+	if perPage <= 0 {
+		perPage = 20
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var specs []map[string]any
+	for _, id := range sortedKeys(s.apiSpecs) {
+		if spec := s.apiSpecs[id]; toInt(spec["client_id"]) == clientID {
+			specs = append(specs, spec)
+		}
+	}
+	// This endpoint has no status/body envelope.
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items":        page(specs, perPage, (pageNum-1)*perPage),
+		"current_page": pageNum,
+		"per_page":     perPage,
+		"total_pages":  (len(specs) + perPage - 1) / perPage,
+		"total_count":  len(specs),
+	})
+}
+
+func (s *Server) apiSpecCreate(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathInt(w, r, "client")
+	if !ok {
+		return
+	}
+	var req map[string]any
+	if !decode(w, r, &req) {
+		return
+	}
+	if url, _ := req["file_remote_url"].(string); url == "" {
+		writeError(w, http.StatusBadRequest, "file_remote_url can't be blank")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now().UTC().Format(time.RFC3339)
+	spec := clone(req)
+	merge(spec, map[string]any{
+		"id":           s.newID(),
+		"client_id":    clientID,
+		"status":       "active",
+		"version":      1,
+		"format":       1,
+		"created_at":   now,
+		"updated_at":   now,
+		"spec_version": "1.0.0",
+	})
+	s.apiSpecs[toInt(spec["id"])] = spec
+	writeBody(w, spec)
+}
+
+func (s *Server) apiSpecRead(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if spec := s.apiSpec(w, r); spec != nil {
+		writeBody(w, spec)
+	}
+}
+
+func (s *Server) apiSpecUpdate(w http.ResponseWriter, r *http.Request) {
+	var req map[string]any
+	if !decode(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	spec := s.apiSpec(w, r)
+	if spec == nil {
+		return
+	}
+	for _, k := range []string{"id", "client_id"} {
+		delete(req, k)
+	}
+	merge(spec, req)
+	spec["version"] = toInt(spec["version"]) + 1
+	spec["updated_at"] = s.now().UTC().Format(time.RFC3339)
+	writeBody(w, spec)
+}
+
+func (s *Server) apiSpecDelete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if spec := s.apiSpec(w, r); spec != nil {
+		delete(s.apiSpecs, toInt(spec["id"]))
+		writeBody(w, map[string]any{"result": "ok"})
+	}
+}
+
+func (s *Server) apiSpecPolicy(w http.ResponseWriter, r *http.Request) {
+	var req map[string]any
+	if !decode(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if spec := s.apiSpec(w, r); spec != nil {
+		spec["policy"] = req
+		writeBody(w, req)
+	}
+}
+
+// defaultDiscoveryConfig is the API discovery config of a new client.
+func defaultDiscoveryConfig(clientID int) map[string]any {
+	return map[string]any{
+		"clientid":                       clientID,
+		"enabled":                        true,
+		"protocols":                      map[string]any{"rest": true, "graphql": true, "soap": true, "grpc": true, "mcp": true},
+		"apply_extended_filter":          true,
+		"type_detection_threshold":       0.5,
+		"pii_detection_threshold":        0.5,
+		"call_points_storage_limit":      1000,
+		"sensitive_samples":              map[string]any{"enabled": false, "min_masked": 0, "max_masked": 0, "mask_symbols": false},
+		"disabled_apps":                  []int{},
+		"endpoint_stability":             map[string]any{"min_count": 5, "min_time": 60},
+		"group_soap":                     false,
+		"server_variability":             map[string]any{"enabled": false, "by_custom_paths": map[string]any{"enabled": false, "paths": []string{}}},
+		"allowed_content_types_patterns": []string{},
+		"extensions_whitelist":           map[string]any{"enabled": false, "extensions": []string{}},
+	}
+}
+
+func (s *Server) discoveryConfig(clientID int) map[string]any {
+	cfg, ok := s.discovery[clientID]
+	if !ok {
+		cfg = defaultDiscoveryConfig(clientID)
+		s.discovery[clientID] = cfg
+	}
+	return cfg
+}
+
+func (s *Server) discoveryRead(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathInt(w, r, "client")
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeBody(w, s.discoveryConfig(clientID))
+}
+
+func (s *Server) discoveryUpdate(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathInt(w, r, "client")
+	if !ok {
+		return
+	}
+	var req map[string]any
+	if !decode(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg := s.discoveryConfig(clientID)
+	merge(cfg, req)
+	cfg["clientid"] = clientID
+	writeBody(w, cfg)
+}
@@ -0,0 +1,403 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// hint is a rule. fields holds the type-specific attributes as sent by the
+// client, so every rule type round-trips without a per-type model.
+type hint struct {
+	id        int
+	actionID  int
+	clientID  int
+	typ       string
+	system    bool
+	createdAt int64
+	updatedAt int64
+	fields    map[string]any
+}
+
+// action is the set of conditions hints are attached to. Actions outlive
+// their hints, as in the Cloud.
+type action struct {
+	id         int
+	clientID   int
+	conditions []any
+	key        string
+	updatedAt  int64
+}
+
+func (h *hint) body(a *action) map[string]any {
+	b := clone(h.fields)
+	merge(b, map[string]any{
+		"id":            h.id,
+		"actionid":      h.actionID,
+		"clientid":      h.clientID,
+		"type":          h.typ,
+		"system":        h.system,
+		"create_time":   h.createdAt,
+		"create_userid": 1,
+		"updated_at":    h.updatedAt,
+		"validated":     true,
+		"action":        a.conditions,
+	})
+	if _, ok := b["regex_id"]; !ok {
+		b["regex_id"] = nil
+	}
+	return b
+}
+
+func (a *action) body() map[string]any {
+	return map[string]any{
+		"id":                a.id,
+		"clientid":          a.clientID,
+		"name":              nil,
+		"conditions":        a.conditions,
+		"endpoint_path":     nil,
+		"endpoint_domain":   nil,
+		"endpoint_instance": nil,
+		"updated_at":        a.updatedAt,
+	}
+}
+
+func (s *Server) hintRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v1/objects/hint", s.hintRead)
+	mux.HandleFunc("POST /v1/objects/hint/count", s.hintCount)
+	mux.HandleFunc("POST /v1/objects/hint/create", s.hintCreate)
+	mux.HandleFunc("POST /v1/objects/hint/delete", s.hintDelete)
+	mux.HandleFunc("PUT /v3/hint/{id}", s.hintUpdate)
+	mux.HandleFunc("POST /v1/objects/action", s.actionList)
+	mux.HandleFunc("GET /v3/action/{id}", s.actionRead)
+}
+
+// normalizeConditions applies the server-side normalization of action
+// conditions: iequal values are downcased (before_validation
+// :iequal_values_downcase). It returns the conditions and a key that is equal
+// for condition sets the Cloud treats as one action.
+func normalizeConditions(raw []any) ([]any, string) {
+	conds := make([]any, 0, len(raw))
+	keys := make([]string, 0, len(raw))
+	for _, c := range raw {
+		m, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		m = clone(m)
+		if t, _ := m["type"].(string); t == "iequal" {
+			if v, ok := m["value"].(string); ok {
+				m["value"] = strings.ToLower(v)
+			}
+		}
+		conds = append(conds, m)
+		k, _ := json.Marshal(m)
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+	return conds, strings.Join(keys, "\n")
+}
+
+// actionFor returns the action of clientID with the given conditions,
+// creating it if there is none: hints with equal conditions share an action.
+func (s *Server) actionFor(clientID int, raw []any) *action {
+	conds, key := normalizeConditions(raw)
+	for _, id := range sortedKeys(s.actions) {
+		if a := s.actions[id]; a.clientID == clientID && a.key == key {
+			return a
+		}
+	}
+	a := &action{id: s.newID(), clientID: clientID, conditions: conds, key: key, updatedAt: s.now().Unix()}
+	s.actions[a.id] = a
+	return a
+}
+
+// SeedHint stores a rule as if it had been created through the API and
+// returns its ID. fields is an ActionCreate-shaped object.
+func (s *Server) SeedHint(fields map[string]any) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.createHint(fields).id
+}
+
+// HintCount returns the number of rules stored.
+func (s *Server) HintCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.hints)
+}
+
+// HintIDs returns the IDs of the rules stored, in ascending order.
+func (s *Server) HintIDs() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedKeys(s.hints)
+}
+
+// ActionCount returns the number of actions stored.
+func (s *Server) ActionCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.actions)
+}
+
+// DeleteHintOutOfBand removes a rule as if it had been deleted in the UI, for
+// drift tests. It reports whether the rule existed.
+func (s *Server) DeleteHintOutOfBand(id int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.hints[id]
+	delete(s.hints, id)
+	return ok
+}
+
+// UpdateHintOutOfBand changes fields of a rule behind the provider's back.
+func (s *Server) UpdateHintOutOfBand(id int, fields map[string]any) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, ok := s.hints[id]
+	if ok {
+		merge(h.fields, fields)
+		h.updatedAt = s.now().Unix()
+	}
+	return ok
+}
+
+func (s *Server) createHint(fields map[string]any) *hint {
+	fields = clone(fields)
+	clientID := toInt(fields["clientid"])
+	if clientID == 0 {
+		clientID = DefaultClientID
+	}
+	conds, _ := fields["action"].([]any)
+	a := s.actionFor(clientID, conds)
+	typ, _ := fields["type"].(string)
+	for _, k := range []string{"id", "actionid", "clientid", "type", "action", "validated"} {
+		delete(fields, k)
+	}
+	now := s.now().Unix()
+	h := &hint{id: s.newID(), actionID: a.id, clientID: clientID, typ: typ, createdAt: now, updatedAt: now, fields: fields}
+	if (typ == "regex" || typ == "experimental_regex") && fields["regex_id"] == nil {
+		h.fields["regex_id"] = h.id
+	}
+	s.hints[h.id] = h
+	a.updatedAt = now
+	return h
+}
+
+func (s *Server) hintCreate(w http.ResponseWriter, r *http.Request) {
+	var req map[string]any
+	if !decode(w, r, &req) {
+		return
+	}
+	if t, _ := req["type"].(string); t == "" {
+		writeError(w, http.StatusBadRequest, "type can't be blank")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h := s.createHint(req)
+	writeBody(w, h.body(s.actions[h.actionID]))
+}
+
+func (s *Server) hintUpdate(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	var req map[string]any
+	if !decode(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, ok := s.hints[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	merge(h.fields, req)
+	h.updatedAt = s.now().Unix()
+	writeBody(w, h.body(s.actions[h.actionID]))
+}
+
+// hintFilter is the subset of the objects API hint filter the provider sends.
+type hintFilter struct {
+	Clientid  []int    `json:"clientid"`
+	ActionID  []int    `json:"actionid"`
+	ID        []int    `json:"id"`
+	NotID     []int    `json:"!id"`
+	Type      []string `json:"type"`
+	System    *bool    `json:"system"`
+	UpdatedAt [][]int  `json:"updated_at"`
+}
+
+func (f *hintFilter) match(h *hint) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Clientid) > 0 && !containsInt(f.Clientid, h.clientID) ||
+		len(f.ActionID) > 0 && !containsInt(f.ActionID, h.actionID) ||
+		len(f.ID) > 0 && !containsInt(f.ID, h.id) ||
+		containsInt(f.NotID, h.id) ||
+		f.System != nil && *f.System != h.system {
+		return false
+	}
+	if len(f.Type) > 0 {
+		found := false
+		for _, t := range f.Type {
+			found = found || t == h.typ
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, rng := range f.UpdatedAt {
+		if len(rng) == 2 && (h.updatedAt < int64(rng[0]) || h.updatedAt > int64(rng[1])) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) filterHints(f *hintFilter, desc bool) []*hint {
+	var hints []*hint
+	for _, id := range sortedKeys(s.hints) {
+		if h := s.hints[id]; f.match(h) {
+			hints = append(hints, h)
+		}
+	}
+	if desc {
+		for i, j := 0, len(hints)-1; i < j; i, j = i+1, j-1 {
+			hints[i], hints[j] = hints[j], hints[i]
+		}
+	}
+	return hints
+}
+
+func (s *Server) hintRead(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Filter    *hintFilter `json:"filter"`
+		OrderDesc bool        `json:"order_desc"`
+		Limit     int         `json:"limit"`
+		Offset    int         `json:"offset"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	body := []map[string]any{}
+	for _, h := range page(s.filterHints(req.Filter, req.OrderDesc), req.Limit, req.Offset) {
+		body = append(body, h.body(s.actions[h.actionID]))
+	}
+	writeBody(w, body)
+}
+
+func (s *Server) hintCount(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Filter *hintFilter `json:"filter"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeBody(w, len(s.filterHints(req.Filter, false)))
+}
+
+// hintDelete deletes hints by ID. Like the Cloud it answers 200 even when
+// nothing matched, and never deletes counter hints (a trigger owns them); the
+// body lists what was actually deleted.
+func (s *Server) hintDelete(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Filter *struct {
+			Clientid []int `json:"clientid"`
+			ID       []int `json:"id"`
+		} `json:"filter"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Filter == nil || len(req.Filter.ID) == 0 {
+		writeError(w, http.StatusBadRequest, "filter.id can't be blank")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := []map[string]any{}
+	for _, id := range req.Filter.ID {
+		h, ok := s.hints[id]
+		if !ok || len(req.Filter.Clientid) > 0 && !containsInt(req.Filter.Clientid, h.clientID) ||
+			strings.HasSuffix(h.typ, "_counter") {
+			continue
+		}
+		deleted = append(deleted, h.body(s.actions[h.actionID]))
+		delete(s.hints, id)
+	}
+	writeBody(w, deleted)
+}
+
+func (s *Server) actionList(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Filter *struct {
+			ID       []int    `json:"id"`
+			NotID    []int    `json:"!id"`
+			Clientid []int    `json:"clientid"`
+			HintType []string `json:"hint_type"`
+			Empty    *bool    `json:"empty"`
+		} `json:"filter"`
+		Limit  int `json:"limit"`
+		Offset int `json:"offset"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matched []map[string]any
+	for _, id := range sortedKeys(s.actions) {
+		a := s.actions[id]
+		if f := req.Filter; f != nil {
+			if len(f.ID) > 0 && !containsInt(f.ID, a.id) ||
+				containsInt(f.NotID, a.id) ||
+				len(f.Clientid) > 0 && !containsInt(f.Clientid, a.clientID) ||
+				f.Empty != nil && *f.Empty != (len(a.conditions) == 0) ||
+				len(f.HintType) > 0 && !s.actionHasHintType(a.id, f.HintType) {
+				continue
+			}
+		}
+		matched = append(matched, a.body())
+	}
+	writeBody(w, page(matched, req.Limit, req.Offset))
+}
+
+func (s *Server) actionHasHintType(actionID int, types []string) bool {
+	for _, h := range s.hints {
+		if h.actionID != actionID {
+			continue
+		}
+		for _, t := range types {
+			if h.typ == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Server) actionRead(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.actions[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	writeBody(w, a.body())
+}
@@ -0,0 +1,124 @@
+package fakeapi
+
+import (
+	"net/http"
+	"strconv"
+)
+
+func (s *Server) integrationRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v2/integration", s.integrationList)
+	mux.HandleFunc("POST /v2/integration", s.integrationCreate)
+	mux.HandleFunc("POST /v2/integration/telegram", s.integrationCreate)
+	mux.HandleFunc("PUT /v2/integration/{id}", s.integrationUpdate)
+	mux.HandleFunc("DELETE /v2/integration/{id}", s.integrationDelete)
+	mux.HandleFunc("POST /v2/integration/{id}/test", s.integrationTest)
+}
+
+// IntegrationCount returns the number of integrations stored.
+func (s *Server) IntegrationCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.integrations)
+}
+
+func writeIntegration(w http.ResponseWriter, obj any) {
+	writeJSON(w, http.StatusOK, map[string]any{"body": map[string]any{"result": "success", "object": obj}})
+}
+
+func (s *Server) integrationList(w http.ResponseWriter, r *http.Request) {
+	clientID, _ := strconv.Atoi(r.URL.Query().Get("clientid"))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	objects := []map[string]any{}
+	for _, id := range sortedKeys(s.integrations) {
+		if obj := s.integrations[id]; clientID == 0 || toInt(obj["clientid"]) == clientID {
+			objects = append(objects, obj)
+		}
+	}
+	writeIntegration(w, objects)
+}
+
+func (s *Server) integrationCreate(w http.ResponseWriter, r *http.Request) {
+	var req map[string]any
+	if !decode(w, r, &req) {
+		return
+	}
+	if r.URL.Path == "/v2/integration/telegram" {
+		req["type"] = "telegram"
+		req["active"] = true
+		req["target"] = req["chat_data"]
+		delete(req, "token")
+	}
+	if t, _ := req["type"].(string); t == "" {
+		writeError(w, http.StatusBadRequest, "type can't be blank")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj := clone(req)
+	obj["id"] = s.newID()
+	if toInt(obj["clientid"]) == 0 {
+		obj["clientid"] = DefaultClientID
+	}
+	obj["created_at"] = s.now().Unix()
+	obj["created_by"] = "fakeapi"
+	if obj["events"] == nil {
+		obj["events"] = []any{}
+	}
+	s.integrations[toInt(obj["id"])] = obj
+	writeIntegration(w, obj)
+}
+
+// integrationUpdate merges the sent fields, so both full and partial updates
+// (IntegrationPartialUpdate) work.
+func (s *Server) integrationUpdate(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	var req map[string]any
+	if !decode(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.integrations[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	delete(req, "id")
+	merge(obj, req)
+	writeIntegration(w, obj)
+}
+
+func (s *Server) integrationDelete(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.integrations[id]; !ok {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	delete(s.integrations, id)
+	writeBody(w, map[string]any{"result": "ok"})
+}
+
+// integrationTest accepts every test delivery of an existing integration.
+func (s *Server) integrationTest(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	_, ok = s.integrations[id]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	writeBody(w, map[string]any{"result": "ok"})
+}
@@ -0,0 +1,182 @@
+package fakeapi
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ipGroup is one access rule group: the values of one rule type added by one
+// request. The fake stores one value per group, like the provider's creates.
+type ipGroup struct {
+	ID             int      `json:"id"`
+	ClientID       int      `json:"client_id"`
+	RuleType       string   `json:"rule_type"`
+	List           string   `json:"list"`
+	CreatedAt      int64    `json:"created_at"`
+	ExpiredAt      int      `json:"expired_at"`
+	ApplicationIDs []int    `json:"application_ids"`
+	Reason         string   `json:"reason"`
+	AuthorUserID   int      `json:"author_user_id"`
+	Values         []string `json:"values"`
+	Status         string   `json:"status"`
+}
+
+func (s *Server) ipListRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/blocklist/clients/{client}/groups", s.ipGroupList)
+	mux.HandleFunc("POST /v1/blocklist/clients/{client}/access_rules", s.ipRuleCreate)
+	mux.HandleFunc("DELETE /v1/blocklist/clients/{client}/groups", s.ipGroupDelete)
+}
+
+// normalizeSubnet stores single addresses as host routes, as the Cloud does.
+func normalizeSubnet(v string) string {
+	if strings.Contains(v, "/") {
+		return v
+	}
+	ip := net.ParseIP(v)
+	switch {
+	case ip == nil:
+		return v
+	case ip.To4() != nil:
+		return v + "/32"
+	default:
+		return v + "/128"
+	}
+}
+
+// IPGroupCount returns the number of access rule groups on a list.
+func (s *Server) IPGroupCount(list string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, g := range s.ipGroups {
+		if g.List == list {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Server) ipGroupList(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathInt(w, r, "client")
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	ruleTypes := q["filter[rule_type][]"]
+	list := q.Get("filter[list]")
+	query := q.Get("filter[query]")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var groups []*ipGroup
+	for _, id := range sortedKeys(s.ipGroups) {
+		g := s.ipGroups[id]
+		if g.ClientID != clientID || list != "" && g.List != list {
+			continue
+		}
+		if len(ruleTypes) > 0 && !containsString(ruleTypes, g.RuleType) {
+			continue
+		}
+		if query != "" && !groupMatches(g, query) {
+			continue
+		}
+		groups = append(groups, g)
+	}
+	writeBody(w, map[string]any{"objects": page(groups, queryInt(r, "limit"), queryInt(r, "offset"))})
+}
+
+func groupMatches(g *ipGroup, query string) bool {
+	for _, v := range g.Values {
+		if v == query || v == normalizeSubnet(query) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) ipRuleCreate(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathInt(w, r, "client")
+	if !ok {
+		return
+	}
+	var req struct {
+		List           string `json:"list"`
+		Force          bool   `json:"force"`
+		Reason         string `json:"reason"`
+		ApplicationIDs []int  `json:"application_ids"`
+		ExpiredAt      int    `json:"expired_at"`
+		Rules          []struct {
+			RulesType string   `json:"rules_type"`
+			Values    []string `json:"values"`
+		} `json:"rules"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.List == "" || len(req.Rules) == 0 {
+		writeError(w, http.StatusBadRequest, "list and rules can't be blank")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now().Unix()
+	for _, rule := range req.Rules {
+		for _, v := range rule.Values {
+			if rule.RulesType == "subnet" {
+				v = normalizeSubnet(v)
+			}
+			// Adding a value that is already on the list replaces its group.
+			for id, g := range s.ipGroups {
+				if g.ClientID == clientID && g.List == req.List && g.RuleType == rule.RulesType && containsString(g.Values, v) {
+					delete(s.ipGroups, id)
+				}
+			}
+			g := &ipGroup{
+				ID: s.newID(), ClientID: clientID, RuleType: rule.RulesType, List: req.List,
+				CreatedAt: now, ExpiredAt: req.ExpiredAt, ApplicationIDs: req.ApplicationIDs,
+				Reason: req.Reason, AuthorUserID: 1, Values: []string{v}, Status: "active",
+			}
+			if g.ApplicationIDs == nil {
+				g.ApplicationIDs = []int{}
+			}
+			s.ipGroups[g.ID] = g
+		}
+	}
+	writeBody(w, map[string]any{"result": "ok"})
+}
+
+func (s *Server) ipGroupDelete(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathInt(w, r, "client")
+	if !ok {
+		return
+	}
+	var req struct {
+		Rules []struct {
+			RuleType string `json:"rule_type"`
+			IDs      []int  `json:"ids"`
+		} `json:"rules"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rule := range req.Rules {
+		for _, id := range rule.IDs {
+			if g, ok := s.ipGroups[id]; ok && g.ClientID == clientID {
+				delete(s.ipGroups, id)
+			}
+		}
+	}
+	writeBody(w, map[string]any{"result": "ok"})
+}
+
+func containsString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
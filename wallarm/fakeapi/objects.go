@@ -0,0 +1,393 @@
+package fakeapi
+
+import (
+	"net/http"
+	"time"
+)
+
+// appKey identifies an application: pool IDs are unique per client only.
+type appKey struct {
+	clientID int
+	id       int
+}
+
+type app struct {
+	ID       int    `json:"id"`
+	Clientid int    `json:"clientid"`
+	Name     string `json:"name"`
+	Deleted  bool   `json:"deleted"`
+}
+
+func (s *Server) objectRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v1/user", s.userDetails)
+
+	mux.HandleFunc("POST /v1/objects/pool", s.appRead)
+	mux.HandleFunc("POST /v1/objects/pool/create", s.appCreate)
+	mux.HandleFunc("POST /v1/objects/pool/update", s.appUpdate)
+	mux.HandleFunc("POST /v1/objects/pool/delete", s.appDelete)
+
+	mux.HandleFunc("POST /v1/objects/user", s.userRead)
+	mux.HandleFunc("POST /v1/objects/user/create", s.userCreate)
+	mux.HandleFunc("POST /v1/objects/user/update", s.userUpdate)
+	mux.HandleFunc("POST /v1/objects/user/delete", s.userDelete)
+
+	mux.HandleFunc("GET /v1/objects/client", s.clientRead)
+	mux.HandleFunc("POST /v1/objects/client/create", s.clientCreate)
+	mux.HandleFunc("POST /v1/objects/client/update", s.clientUpdate)
+	mux.HandleFunc("POST /v1/objects/client/delete", s.clientDelete)
+}
+
+// userDetails describes the authenticated user, whose client is the default
+// client of the provider.
+func (s *Server) userDetails(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeBody(w, map[string]any{
+		"id":          1,
+		"uuid":        fakeUUID(1),
+		"client_id":   DefaultClientID,
+		"clientid":    DefaultClientID,
+		"email":       "admin@fakeapi.local",
+		"username":    "admin@fakeapi.local",
+		"realname":    "Fake API",
+		"permissions": []string{"admin"},
+		"enabled":     true,
+		"create_at":   s.now().UTC().Format(time.RFC3339),
+	})
+}
+
+func (s *Server) appRead(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Limit  int `json:"limit"`
+		Offset int `json:"offset"`
+		Filter *struct {
+			Clientid []int `json:"clientid"`
+		} `json:"filter"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var apps []*app
+	for _, a := range s.sortedApps() {
+		if req.Filter == nil || len(req.Filter.Clientid) == 0 || containsInt(req.Filter.Clientid, a.Clientid) {
+			apps = append(apps, a)
+		}
+	}
+	writeBody(w, page(apps, req.Limit, req.Offset))
+}
+
+func (s *Server) sortedApps() []*app {
+	byID := make(map[int][]*app)
+	for _, a := range s.apps {
+		byID[a.ID] = append(byID[a.ID], a)
+	}
+	var apps []*app
+	for _, id := range sortedKeys(byID) {
+		apps = append(apps, byID[id]...)
+	}
+	return apps
+}
+
+func (s *Server) appCreate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID       *int   `json:"id"`
+		Clientid int    `json:"clientid"`
+		Name     string `json:"name"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Clientid == 0 {
+		req.Clientid = DefaultClientID
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := 1
+	if req.ID != nil {
+		id = *req.ID
+	} else {
+		for s.apps[appKey{req.Clientid, id}] != nil {
+			id++
+		}
+	}
+	key := appKey{req.Clientid, id}
+	if s.apps[key] != nil {
+		// wallarm-go matches this exact body to return ErrExistingResource.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":400,"body":"Already exists"}`))
+		return
+	}
+	a := &app{ID: id, Clientid: req.Clientid, Name: req.Name}
+	s.apps[key] = a
+	writeBody(w, a)
+}
+
+type appFilter struct {
+	ID       int `json:"id"`
+	Clientid any `json:"clientid"` // a number or, on update, a list
+}
+
+func (f *appFilter) key(defaultClient int) appKey {
+	clientID := toInt(f.Clientid)
+	if ids := toInts(f.Clientid); len(ids) > 0 {
+		clientID = ids[0]
+	}
+	if clientID == 0 {
+		clientID = defaultClient
+	}
+	return appKey{clientID, f.ID}
+}
+
+func (s *Server) appUpdate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Filter *appFilter `json:"filter"`
+		Fields *struct {
+			Name string `json:"name"`
+		} `json:"fields"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Filter == nil || req.Fields == nil {
+		writeError(w, http.StatusBadRequest, "filter and fields can't be blank")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.apps[req.Filter.key(DefaultClientID)]
+	if a == nil {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	a.Name = req.Fields.Name
+	writeBody(w, []*app{a})
+}
+
+func (s *Server) appDelete(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Filter *appFilter `json:"filter"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Filter == nil {
+		writeError(w, http.StatusBadRequest, "filter can't be blank")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := req.Filter.key(DefaultClientID)
+	if s.apps[key] == nil {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	delete(s.apps, key)
+	writeBody(w, []int{key.id})
+}
+
+type userFilter struct {
+	ID       int    `json:"id"`
+	Clientid []int  `json:"clientid"`
+	UUID     string `json:"uuid"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+func (f *userFilter) match(u map[string]any) bool {
+	return f == nil ||
+		(f.ID == 0 || toInt(u["id"]) == f.ID) &&
+			(len(f.Clientid) == 0 || containsInt(f.Clientid, toInt(u["clientid"]))) &&
+			(f.UUID == "" || u["uuid"] == f.UUID) &&
+			(f.Username == "" || u["username"] == f.Username) &&
+			(f.Email == "" || u["email"] == f.Email)
+}
+
+func (s *Server) matchUsers(f *userFilter) []map[string]any {
+	users := []map[string]any{}
+	for _, id := range sortedKeys(s.users) {
+		if u := s.users[id]; f.match(u) {
+			users = append(users, u)
+		}
+	}
+	return users
+}
+
+func (s *Server) userRead(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Limit  int         `json:"limit"`
+		Filter *userFilter `json:"filter"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeBody(w, page(s.matchUsers(req.Filter), req.Limit, 0))
+}
+
+func (s *Server) userCreate(w http.ResponseWriter, r *http.Request) {
+	var req map[string]any
+	if !decode(w, r, &req) {
+		return
+	}
+	email, _ := req["email"].(string)
+	if email == "" {
+		writeError(w, http.StatusBadRequest, "email can't be blank")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.matchUsers(&userFilter{Email: email})) > 0 {
+		writeError(w, http.StatusConflict, "User with this email already exists")
+		return
+	}
+	u := clone(req)
+	delete(u, "password")
+	id := s.newID()
+	u["id"] = id
+	u["uuid"] = fakeUUID(id)
+	if toInt(u["clientid"]) == 0 {
+		u["clientid"] = DefaultClientID
+	}
+	if u["username"] == nil || u["username"] == "" {
+		u["username"] = email
+	}
+	u["actual_permissions"] = u["permissions"]
+	u["create_at"] = s.now().Unix()
+	s.users[id] = u
+	writeBody(w, u)
+}
+
+func (s *Server) userUpdate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Filter *userFilter    `json:"filter"`
+		Fields map[string]any `json:"fields"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	users := s.matchUsers(req.Filter)
+	if len(users) == 0 {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	delete(req.Fields, "password")
+	for _, u := range users {
+		merge(u, req.Fields)
+		if p, ok := req.Fields["permissions"]; ok {
+			u["actual_permissions"] = p
+		}
+	}
+	writeBody(w, users)
+}
+
+func (s *Server) userDelete(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Filter *userFilter `json:"filter"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Filter == nil {
+		writeError(w, http.StatusBadRequest, "filter can't be blank")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range s.matchUsers(req.Filter) {
+		delete(s.users, toInt(u["id"]))
+	}
+	writeBody(w, map[string]any{"result": "ok"})
+}
+
+func (s *Server) clientRead(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clients := []map[string]any{}
+	for _, id := range sortedKeys(s.clients) {
+		c := s.clients[id]
+		if v := q.Get("filter[id]"); v != "" && toInt(v) != id ||
+			q.Get("filter[name]") != "" && c["name"] != q.Get("filter[name]") ||
+			q.Get("filter[uuid]") != "" && c["uuid"] != q.Get("filter[uuid]") ||
+			q.Get("filter[enabled]") == "true" && c["enabled"] != true {
+			continue
+		}
+		clients = append(clients, c)
+	}
+	writeBody(w, page(clients, queryInt(r, "limit"), queryInt(r, "offset")))
+}
+
+func (s *Server) clientCreate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name        string `json:"name"`
+		PartnerUUID string `json:"partner_uuid"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name can't be blank")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.newID()
+	c := map[string]any{
+		"id": id, "uuid": fakeUUID(id), "name": req.Name, "enabled": true, "validated": true,
+		"partnerid": DefaultClientID, "partner_uuid": req.PartnerUUID, "create_at": s.now().Unix(),
+	}
+	s.clients[id] = c
+	writeBody(w, c)
+}
+
+func (s *Server) clientUpdate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Filter *struct {
+			ID int `json:"id"`
+		} `json:"filter"`
+		Fields map[string]any `json:"fields"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Filter == nil {
+		writeError(w, http.StatusBadRequest, "filter can't be blank")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.clients[req.Filter.ID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	merge(c, req.Fields)
+	writeBody(w, []map[string]any{c})
+}
+
+func (s *Server) clientDelete(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Filter *struct {
+			ID int `json:"id"`
+		} `json:"filter"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := []int{}
+	if req.Filter != nil {
+		if _, ok := s.clients[req.Filter.ID]; ok && req.Filter.ID != DefaultClientID {
+			delete(s.clients, req.Filter.ID)
+			deleted = append(deleted, req.Filter.ID)
+		}
+	}
+	writeBody(w, deleted)
+}
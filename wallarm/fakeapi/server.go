@@ -0,0 +1,274 @@
+// Package fakeapi is a stateful in-memory fake of the Wallarm API endpoints
+// the provider uses, for unit tests that exercise real HTTP round trips
+// through wallarm-go and the provider's transports without a Cloud tenant.
+//
+// It enforces the server-side behaviors the provider relies on and that are
+// documented in references/: iequal condition values are downcased, a hint
+// joins the existing action with the same conditions, list endpoints
+// paginate, a hint delete that matches nothing succeeds with an empty body,
+// and injected 423/429 responses exercise the retry paths.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultClientID is the client of the user the fake API authenticates, and
+// of objects created without a clientid.
+const DefaultClientID = 1
+
+// Server is a fake Wallarm API on a local httptest server. Its state is
+// shared by every request; tests may seed and inspect it through the
+// exported methods, which are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	nextID   int
+	now      func() time.Time
+	faults   []*fault
+	requests []string
+
+	hints        map[int]*hint
+	actions      map[int]*action
+	ipGroups     map[int]*ipGroup
+	triggers     map[int]*trigger
+	integrations map[int]map[string]any
+	apps         map[appKey]*app
+	users        map[int]map[string]any
+	clients      map[int]map[string]any
+	apiSpecs     map[int]map[string]any
+	discovery    map[int]map[string]any
+}
+
+type fault struct {
+	method string
+	path   string
+	status int
+	left   int
+}
+
+// New starts a fake API server. Call Close when done.
+func New() *Server {
+	s := &Server{
+		nextID:       1000,
+		now:          time.Now,
+		hints:        make(map[int]*hint),
+		actions:      make(map[int]*action),
+		ipGroups:     make(map[int]*ipGroup),
+		triggers:     make(map[int]*trigger),
+		integrations: make(map[int]map[string]any),
+		apps:         make(map[appKey]*app),
+		users:        make(map[int]map[string]any),
+		clients:      make(map[int]map[string]any),
+		apiSpecs:     make(map[int]map[string]any),
+		discovery:    make(map[int]map[string]any),
+	}
+	s.clients[DefaultClientID] = map[string]any{
+		"id": DefaultClientID, "uuid": fakeUUID(DefaultClientID), "name": "Fake tenant",
+		"enabled": true, "validated": true, "partnerid": 0, "partner_uuid": "",
+		"create_at": s.now().Unix(),
+	}
+	s.Server = httptest.NewServer(s.routes())
+	return s
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	s.hintRoutes(mux)
+	s.ipListRoutes(mux)
+	s.triggerRoutes(mux)
+	s.integrationRoutes(mux)
+	s.objectRoutes(mux)
+	s.apiSpecRoutes(mux)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("fakeapi: no handler for %s %s", r.Method, r.URL.Path))
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		status := s.takeFault(r)
+		s.mu.Unlock()
+
+		if r.Header.Get("X-WallarmAPI-Token") == "" &&
+			(r.Header.Get("X-WallarmAPI-UUID") == "" || r.Header.Get("X-WallarmAPI-Secret") == "") {
+			writeError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		switch status {
+		case 0:
+		case http.StatusTooManyRequests:
+			w.Header().Set("Retry-After", "0")
+			writeError(w, status, "Too Many Requests")
+			return
+		case http.StatusLocked:
+			writeError(w, status, "Rules are being updated, try again later")
+			return
+		default:
+			writeError(w, status, http.StatusText(status))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// Fail makes the next n requests to method and path (an exact URL path, ""
+// for any) fail with status. 429 responses carry Retry-After: 0.
+func (s *Server) Fail(method, path string, status, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault{method: method, path: path, status: status, left: n})
+}
+
+func (s *Server) takeFault(r *http.Request) int {
+	for _, f := range s.faults {
+		if f.left > 0 && (f.method == "" || f.method == r.Method) && (f.path == "" || f.path == r.URL.Path) {
+			f.left--
+			return f.status
+		}
+	}
+	return 0
+}
+
+// Requests returns "METHOD path" of every request received so far.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// SetNow replaces the clock used for create and update timestamps.
+func (s *Server) SetNow(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+func (s *Server) newID() int {
+	s.nextID++
+	return s.nextID
+}
+
+// writeJSON writes v with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeBody writes the {"status": ..., "body": ...} envelope most endpoints use.
+func writeBody(w http.ResponseWriter, body any) {
+	writeJSON(w, http.StatusOK, map[string]any{"status": http.StatusOK, "body": body})
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]any{"status": status, "body": msg})
+}
+
+// decode reads a JSON request body into v and reports a 400 on failure.
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	b, err := io.ReadAll(r.Body)
+	if err == nil && len(b) > 0 {
+		err = json.Unmarshal(b, v)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func pathInt(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	n, err := strconv.Atoi(r.PathValue(name))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s %q", name, r.PathValue(name)))
+		return 0, false
+	}
+	return n, true
+}
+
+// page applies limit/offset to items; limit <= 0 means no limit.
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[max(offset, 0):]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// toInt converts a decoded JSON number.
+func toInt(v any) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	case json.Number:
+		i, _ := n.Int64()
+		return int(i)
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	}
+	return 0
+}
+
+func toInts(v any) []int {
+	list, _ := v.([]any)
+	ints := make([]int, 0, len(list))
+	for _, x := range list {
+		ints = append(ints, toInt(x))
+	}
+	return ints
+}
+
+func containsInt(list []int, n int) bool {
+	for _, x := range list {
+		if x == n {
+			return true
+		}
+	}
+	return false
+}
+
+// merge copies the top-level keys of src over dst.
+func merge(dst, src map[string]any) {
+	for k, v := range src {
+		dst[k] = v
+	}
+}
+
+func clone(m map[string]any) map[string]any {
+	c := make(map[string]any, len(m))
+	merge(c, m)
+	return c
+}
+
+func fakeUUID(id int) string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", id)
+}
+
+func queryInt(r *http.Request, key string) int {
+	n, _ := strconv.Atoi(r.URL.Query().Get(key))
+	return n
+}
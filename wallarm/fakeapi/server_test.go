@@ -0,0 +1,247 @@
+package fakeapi_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/wallarm/terraform-provider-wallarm/wallarm/fakeapi"
+	wallarm "github.com/wallarm/wallarm-go"
+)
+
+func newClient(t *testing.T) (*fakeapi.Server, wallarm.API) {
+	t.Helper()
+	srv := fakeapi.New()
+	t.Cleanup(srv.Close)
+	api, err := wallarm.New(
+		wallarm.UsingBaseURL(srv.URL),
+		wallarm.Headers(http.Header{"X-WallarmAPI-Token": {"token"}}),
+		wallarm.UsingRetryPolicy(2, 0, 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv, api
+}
+
+func hostCondition(host string) *[]wallarm.ActionDetails {
+	return &[]wallarm.ActionDetails{{Type: "iequal", Point: []any{"header", "HOST"}, Value: host}}
+}
+
+func TestHints_ActionMergingAndIequal(t *testing.T) {
+	srv, api := newClient(t)
+
+	mode, err := api.HintCreate(&wallarm.ActionCreate{Type: "wallarm_mode", Clientid: 1, Action: hostCondition("Example.COM"), Mode: "block"})
+	if err != nil {
+		t.Fatalf("HintCreate: %v", err)
+	}
+	if got := mode.Body.Action[0].Value; got != "example.com" {
+		t.Errorf("iequal value = %v, want it downcased", got)
+	}
+	vpatch, err := api.HintCreate(&wallarm.ActionCreate{Type: "vpatch", Clientid: 1, Action: hostCondition("example.com"), AttackType: "sqli"})
+	if err != nil {
+		t.Fatalf("HintCreate: %v", err)
+	}
+	if vpatch.Body.ActionID != mode.Body.ActionID {
+		t.Errorf("hints with equal conditions got actions %d and %d, want one", mode.Body.ActionID, vpatch.Body.ActionID)
+	}
+	other, _ := api.HintCreate(&wallarm.ActionCreate{Type: "wallarm_mode", Clientid: 1, Action: hostCondition("other.com"), Mode: "monitoring"})
+	if other.Body.ActionID == mode.Body.ActionID || srv.ActionCount() != 2 {
+		t.Errorf("distinct conditions share an action: %d actions", srv.ActionCount())
+	}
+
+	actions, err := api.ActionList(&wallarm.ActionListParams{
+		Filter: &wallarm.ActionListFilter{Clientid: []int{1}, HintType: []string{"vpatch"}}, Limit: 10,
+	})
+	if err != nil || len(actions.Body) != 1 || actions.Body[0].ID != vpatch.Body.ActionID {
+		t.Errorf("ActionList by hint type = %+v, %v", actions, err)
+	}
+
+	mode2 := "monitoring"
+	updated, err := api.HintUpdateV3(mode.Body.ID, &wallarm.HintUpdateV3Params{Mode: &mode2})
+	if err != nil || updated.Body.Mode != "monitoring" || updated.Body.AttackType != "" {
+		t.Errorf("HintUpdateV3 = %+v, %v", updated, err)
+	}
+}
+
+func TestHints_PaginationAndDelete(t *testing.T) {
+	srv, api := newClient(t)
+	for range 5 {
+		srv.SeedHint(map[string]any{"type": "wallarm_mode", "mode": "block"})
+	}
+	srv.SeedHint(map[string]any{"type": "bruteforce_counter", "counter": "b:1"})
+
+	var ids []int
+	for offset := 0; ; offset += 2 {
+		resp, err := api.HintRead(&wallarm.HintRead{Filter: &wallarm.HintFilter{Clientid: []int{1}}, Limit: 2, Offset: offset})
+		if err != nil {
+			t.Fatalf("HintRead: %v", err)
+		}
+		for _, h := range *resp.Body {
+			ids = append(ids, h.ID)
+		}
+		if len(*resp.Body) < 2 {
+			break
+		}
+	}
+	if len(ids) != 6 {
+		t.Fatalf("paginated %d hints, want 6", len(ids))
+	}
+
+	// Deleting a missing hint or a counter is a silent no-op.
+	resp, err := api.HintDelete(&wallarm.HintDelete{Filter: &wallarm.HintDeleteFilter{Clientid: []int{1}, ID: []int{ids[0], 999999, ids[5]}}})
+	if err != nil {
+		t.Fatalf("HintDelete: %v", err)
+	}
+	if len(resp.Body) != 1 || resp.Body[0].ID != ids[0] || srv.HintCount() != 5 {
+		t.Errorf("HintDelete body = %+v, %d hints left", resp.Body, srv.HintCount())
+	}
+}
+
+func TestFail_RetriedByClient(t *testing.T) {
+	srv, api := newClient(t)
+	srv.Fail(http.MethodPost, "/v1/objects/hint", http.StatusTooManyRequests, 1)
+	srv.Fail(http.MethodPost, "/v1/objects/hint", http.StatusLocked, 1)
+	if _, err := api.HintRead(&wallarm.HintRead{Filter: &wallarm.HintFilter{}, Limit: 1}); err != nil {
+		t.Fatalf("HintRead after 429 and 423: %v", err)
+	}
+	if got := len(srv.Requests()); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+
+	srv.Fail("", "", http.StatusLocked, 3)
+	var apiErr *wallarm.APIError
+	if _, err := api.HintRead(&wallarm.HintRead{Filter: &wallarm.HintFilter{}, Limit: 1}); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusLocked {
+		t.Errorf("err = %v, want the 423 after retries run out", err)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	srv := fakeapi.New()
+	defer srv.Close()
+	api, _ := wallarm.New(wallarm.UsingBaseURL(srv.URL), wallarm.UsingRetryPolicy(0, 0, 0))
+	var apiErr *wallarm.APIError
+	if _, err := api.UserDetails(); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("err = %v, want 401", err)
+	}
+}
+
+func TestIPLists(t *testing.T) {
+	srv, api := newClient(t)
+	err := api.IPListCreate(1, wallarm.AccessRuleCreateRequest{
+		List: wallarm.DenylistType, Reason: "test",
+		Rules: []wallarm.AccessRuleEntry{{RulesType: "subnet", Values: []string{"1.2.3.4", "10.0.0.0/8"}}, {RulesType: "location", Values: []string{"DE"}}},
+	})
+	if err != nil {
+		t.Fatalf("IPListCreate: %v", err)
+	}
+	groups, err := api.IPListRead(wallarm.DenylistType, 1, 1)
+	if err != nil || len(groups) != 3 {
+		t.Fatalf("IPListRead with page size 1 = %d groups, %v", len(groups), err)
+	}
+	found, err := api.IPListSearch(wallarm.DenylistType, 1, "subnet", "1.2.3.4")
+	if err != nil || len(found) != 1 || found[0].Values[0] != "1.2.3.4/32" {
+		t.Fatalf("IPListSearch = %+v, %v", found, err)
+	}
+	if err := api.IPListDelete(1, []wallarm.AccessRuleDeleteEntry{{RuleType: "subnet", IDs: []int{found[0].ID}}}); err != nil {
+		t.Fatalf("IPListDelete: %v", err)
+	}
+	if n := srv.IPGroupCount("block"); n != 2 {
+		t.Errorf("groups left = %d, want 2", n)
+	}
+}
+
+func TestObjects(t *testing.T) {
+	_, api := newClient(t)
+
+	u, err := api.UserDetails()
+	if err != nil || u.Body.Clientid != fakeapi.DefaultClientID {
+		t.Fatalf("UserDetails = %+v, %v", u, err)
+	}
+
+	id := 7
+	if err := api.AppCreate(&wallarm.AppCreate{ID: &id, Clientid: 1, Name: "app"}); err != nil {
+		t.Fatalf("AppCreate: %v", err)
+	}
+	if err := api.AppCreate(&wallarm.AppCreate{ID: &id, Clientid: 1, Name: "dup"}); !errors.Is(err, wallarm.ErrExistingResource) {
+		t.Errorf("duplicate AppCreate err = %v, want ErrExistingResource", err)
+	}
+
+	if _, err := api.UserCreate(&wallarm.UserCreate{Email: "a@example.com", Permissions: []string{"analytic"}}); err != nil {
+		t.Fatalf("UserCreate: %v", err)
+	}
+	if _, err := api.UserCreate(&wallarm.UserCreate{Email: "a@example.com"}); !errors.Is(err, wallarm.ErrExistingResource) {
+		t.Errorf("duplicate UserCreate err = %v, want ErrExistingResource", err)
+	}
+
+	tenant, err := api.ClientCreate(&wallarm.ClientCreate{Name: "tenant"})
+	if err != nil {
+		t.Fatalf("ClientCreate: %v", err)
+	}
+	read, err := api.ClientRead(&wallarm.ClientRead{Filter: &wallarm.ClientReadFilter{ClientFilter: wallarm.ClientFilter{ID: tenant.Body.ID}}, Limit: 1})
+	if err != nil || len(read.Body) != 1 || read.Body[0].Name != "tenant" {
+		t.Errorf("ClientRead = %+v, %v", read, err)
+	}
+
+	trig, err := api.TriggerCreate(&wallarm.TriggerCreate{Trigger: &wallarm.TriggerParam{
+		TemplateID: "attacks_exceeded", Enabled: true, Name: "t",
+		Threshold: &wallarm.TriggerThreshold{Operator: "gt", Period: 60, Count: 5},
+		Actions:   &[]wallarm.TriggerActions{{ID: "send_notification"}},
+	}}, 1)
+	if err != nil || trig.Template.ID != "attacks_exceeded" || trig.Threshold.Count != 5 {
+		t.Fatalf("TriggerCreate = %+v, %v", trig, err)
+	}
+
+	integration, err := api.IntegrationCreate(&wallarm.IntegrationCreate{Name: "slack", Type: "slack", Active: true, Target: "https://hooks.slack.com/x", Clientid: 1})
+	if err != nil {
+		t.Fatalf("IntegrationCreate: %v", err)
+	}
+	if _, err := api.IntegrationPartialUpdate(integration.Body.ID, map[string]any{"active": false}); err != nil {
+		t.Fatalf("IntegrationPartialUpdate: %v", err)
+	}
+	obj, err := api.IntegrationRead(1, integration.Body.ID)
+	if err != nil || obj.Active || obj.Name != "slack" {
+		t.Errorf("IntegrationRead = %+v, %v", obj, err)
+	}
+
+	spec, err := api.APISpecCreate(&wallarm.APISpecCreate{ClientID: 1, Title: "spec", FileRemoteURL: "https://example.com/openapi.yaml"})
+	if err != nil {
+		t.Fatalf("APISpecCreate: %v", err)
+	}
+	if err := api.APISpecDelete(1, spec.Body.ID); err != nil {
+		t.Fatalf("APISpecDelete: %v", err)
+	}
+	if _, err := api.APISpecReadByID(1, spec.Body.ID); !errors.Is(err, wallarm.ErrNotFound) {
+		t.Errorf("APISpecReadByID after delete err = %v, want ErrNotFound", err)
+	}
+
+	cfg, err := api.APIDiscoveryConfigRead(1)
+	if err != nil || !cfg.Enabled {
+		t.Fatalf("APIDiscoveryConfigRead = %+v, %v", cfg, err)
+	}
+	cfg.Enabled = false
+	if err := api.APIDiscoveryConfigUpdate(1, cfg); err != nil {
+		t.Fatalf("APIDiscoveryConfigUpdate: %v", err)
+	}
+	if cfg, _ := api.APIDiscoveryConfigRead(1); cfg.Enabled {
+		t.Error("API discovery config update was not stored")
+	}
+}
+
+func TestUnknownEndpoint(t *testing.T) {
+	srv, _ := newClient(t)
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/v9/nothing", nil)
+	req.Header.Set("X-WallarmAPI-Token", "token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+	if got := srv.Requests(); len(got) != 1 || !strings.HasPrefix(got[0], "GET /v9/nothing") {
+		t.Errorf("requests = %v", got)
+	}
+}
@@ -0,0 +1,148 @@
+package fakeapi
+
+import (
+	"net/http"
+)
+
+// trigger is stored as sent and rendered in the denormalized shape of
+// GET /v2/clients/{client}/triggers.
+type trigger struct {
+	id       int
+	clientID int
+	params   map[string]any
+}
+
+func (t *trigger) body() map[string]any {
+	b := map[string]any{
+		"id":        t.id,
+		"client_id": t.clientID,
+		"name":      t.params["name"],
+		"comment":   t.params["comment"],
+		"enabled":   t.params["enabled"],
+		"filters":   t.params["filters"],
+		"template":  map[string]any{"id": t.params["template_id"]},
+	}
+	if b["filters"] == nil {
+		b["filters"] = []any{}
+	}
+	actions := []any{}
+	raw, _ := t.params["actions"].([]any)
+	for _, a := range raw {
+		if m, ok := a.(map[string]any); ok {
+			params, _ := m["params"].(map[string]any)
+			if params == nil {
+				params = map[string]any{}
+			}
+			actions = append(actions, map[string]any{"id": m["id"], "params": params})
+		}
+	}
+	b["actions"] = actions
+	if th, ok := t.params["threshold"].(map[string]any); ok {
+		b["threshold"] = map[string]any{"operator": th["operator"], "period": th["period"], "count": th["count"]}
+	}
+	return b
+}
+
+func (s *Server) triggerRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v2/clients/{client}/triggers", s.triggerList)
+	mux.HandleFunc("POST /v2/clients/{client}/triggers", s.triggerCreate)
+	mux.HandleFunc("PUT /v2/clients/{client}/triggers/{id}", s.triggerUpdate)
+	mux.HandleFunc("DELETE /v2/clients/{client}/triggers/{id}", s.triggerDelete)
+}
+
+// TriggerCount returns the number of triggers stored.
+func (s *Server) TriggerCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.triggers)
+}
+
+func (s *Server) triggerList(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathInt(w, r, "client")
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	triggers := []map[string]any{}
+	for _, id := range sortedKeys(s.triggers) {
+		if t := s.triggers[id]; t.clientID == clientID {
+			triggers = append(triggers, t.body())
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"triggers": triggers})
+}
+
+func decodeTrigger(w http.ResponseWriter, r *http.Request) (map[string]any, bool) {
+	var req struct {
+		Trigger map[string]any `json:"trigger"`
+	}
+	if !decode(w, r, &req) {
+		return nil, false
+	}
+	if req.Trigger == nil || req.Trigger["template_id"] == "" || req.Trigger["template_id"] == nil {
+		writeError(w, http.StatusBadRequest, "trigger.template_id can't be blank")
+		return nil, false
+	}
+	return req.Trigger, true
+}
+
+func (s *Server) triggerCreate(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathInt(w, r, "client")
+	if !ok {
+		return
+	}
+	params, ok := decodeTrigger(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := &trigger{id: s.newID(), clientID: clientID, params: params}
+	s.triggers[t.id] = t
+	writeJSON(w, http.StatusOK, map[string]any{"trigger": t.body()})
+}
+
+func (s *Server) triggerUpdate(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathInt(w, r, "client")
+	if !ok {
+		return
+	}
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	params, ok := decodeTrigger(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.triggers[id]
+	if !ok || t.clientID != clientID {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	t.params = params
+	writeJSON(w, http.StatusOK, map[string]any{"trigger": t.body()})
+}
+
+func (s *Server) triggerDelete(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathInt(w, r, "client")
+	if !ok {
+		return
+	}
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.triggers[id]
+	if !ok || t.clientID != clientID {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	delete(s.triggers, id)
+	writeBody(w, map[string]any{"result": "ok"})
+}
@@ -1,7 +1,9 @@
 package wallarm
 
 import (
+	"context"
 	"fmt"
+	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -93,7 +95,13 @@ const findActionByConditionsHashPageCap = 200
 //
 // expectConditionCount is a cheap length pre-filter to skip the hash compute
 // for actions that can't possibly match. Pass len(action).
+//
+// With hint prefetch (CachedClient) the lookup is served by the ActionIndex,
+// which paginates once per hint type and run.
 func findActionByConditionsHash(client wallarm.API, clientID int, hintType, wantHash string, expectConditionCount int) (*wallarm.ActionEntry, error) {
+	if cached, ok := client.(*CachedClient); ok {
+		return cached.actionIndex.Find(cached.API, actionIndexScope{clientID: clientID, hintType: hintType}, wantHash)
+	}
 	const pageSize = APIListLimit
 	for page := 0; page < findActionByConditionsHashPageCap; page++ {
 		offset := page * pageSize
@@ -130,30 +138,60 @@ func ImportAsExistsError(resourceName, id string) error {
 	return fmt.Errorf("the resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information", id, resourceName)
 }
 
+// Values of the rule and provider on_conflict attribute: what Create does when
+// a rule of the same type already exists on the action scope.
+const (
+	onConflictError   = "error"
+	onConflictAdopt   = "adopt"
+	onConflictReplace = "replace"
+)
+
+var onConflictValues = []string{onConflictError, onConflictAdopt, onConflictReplace}
+
+// ruleOnConflict returns the resource's on_conflict, falling back to the
+// provider default and then to onConflictError.
+func ruleOnConflict(d *schema.ResourceData, m any) string {
+	if v, ok := d.Get("on_conflict").(string); ok && v != "" {
+		return v
+	}
+	if v := m.(*ProviderMeta).OnConflict; v != "" {
+		return v
+	}
+	return onConflictError
+}
+
 // guardExistingHint runs the existingHintForAction collision check on Create
-// and returns ImportAsExistsError diagnostics if a hint of `hintType` is
-// already attached to a matching action scope. idFmt formats the import-id
+// and resolves a collision with a hint of `hintType` according to
+// on_conflict:
+//   - error (default): returns ImportAsExistsError diagnostics.
+//   - adopt: takes the existing rule into state, reconciles its fields through
+//     update and returns adopted=true; Create must then only Read.
+//   - replace: deletes the existing rule; Create proceeds as usual.
+//
+// Adopt and replace log the existing rule's import ID and return it in a
+// warning diagnostic, which Create must pass on. idFmt formats the import-id
 // from (clientID, actionID, matched rule); pass nil for the default 3-part
 // `{clientID}/{actionID}/{ruleID}` format. Resources with a 4-part import ID
 // (e.g., wallarm_rule_mode) pass an idFmt that appends their suffix.
 func guardExistingHint(
-	d *schema.ResourceData, m any,
+	ctx context.Context, d *schema.ResourceData, m any,
 	hintType, resourceName string,
 	idFmt func(clientID, actionID int, r *wallarm.ActionBody) string,
-) diag.Diagnostics {
+	update schema.UpdateContextFunc,
+) (adopted bool, diags diag.Diagnostics) {
 	if !d.IsNewResource() {
-		return nil
+		return false, nil
 	}
 	actionID, rule, exists, err := existingHintForAction(d, m, hintType)
 	if err != nil {
-		return diag.FromErr(err)
+		return false, diag.FromErr(err)
 	}
 	if !exists {
-		return nil
+		return false, nil
 	}
 	clientID, err := retrieveClientID(d, m)
 	if err != nil {
-		return diag.FromErr(err)
+		return false, diag.FromErr(err)
 	}
 	var existingID string
 	if idFmt != nil {
@@ -161,5 +199,42 @@ func guardExistingHint(
 	} else {
 		existingID = fmt.Sprintf("%d/%d/%d", clientID, actionID, rule.ID)
 	}
-	return diag.FromErr(ImportAsExistsError(resourceName, existingID))
+
+	switch onConflict := ruleOnConflict(d, m); onConflict {
+	case onConflictAdopt:
+		log.Printf("[INFO] %s: adopting existing rule with import ID %q (on_conflict = %q)", resourceName, existingID, onConflict)
+		d.SetId(existingID)
+		d.Set("rule_id", rule.ID)
+		d.Set("action_id", actionID)
+		d.Set("rule_type", rule.Type)
+		if diags := update(ctx, d, m); diags.HasError() {
+			return true, diags
+		}
+		return true, diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Adopted existing %s", resourceName),
+			Detail: fmt.Sprintf("A rule of type %q already existed on this action scope and was taken over "+
+				"instead of created (on_conflict = %q). Its import ID is %q.", hintType, onConflict, existingID),
+		}}
+	case onConflictReplace:
+		log.Printf("[INFO] %s: deleting existing rule with import ID %q to recreate it (on_conflict = %q)", resourceName, existingID, onConflict)
+		resp, err := apiClient(m).HintDelete(&wallarm.HintDelete{
+			Filter: &wallarm.HintDeleteFilter{
+				Clientid: []int{clientID},
+				ID:       []int{rule.ID},
+			},
+		})
+		if err != nil {
+			return false, diag.FromErr(fmt.Errorf("failed to replace existing rule %q: %w", existingID, err))
+		}
+		resourcerule.LogIfHintDeleteNoOp(resp, rule.ID)
+		return false, diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Replaced existing %s", resourceName),
+			Detail: fmt.Sprintf("A rule of type %q already existed on this action scope and was deleted "+
+				"before creating this one (on_conflict = %q). Its import ID was %q.", hintType, onConflict, existingID),
+		}}
+	default:
+		return false, diag.FromErr(ImportAsExistsError(resourceName, existingID))
+	}
 }
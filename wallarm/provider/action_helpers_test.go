@@ -1,9 +1,12 @@
 package wallarm
 
 import (
+	"context"
+	"strings"
 	"sync/atomic"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/wallarm/terraform-provider-wallarm/wallarm/common/resourcerule"
 	wallarm "github.com/wallarm/wallarm-go"
@@ -260,3 +263,78 @@ func TestExistingHintForAction_ActionMatchesButNoHint(t *testing.T) {
 		t.Errorf("expected (0, nil, false, nil) when action matches but no hint, got (%d, %+v, %v)", actionID, rule, exists)
 	}
 }
+
+// TestGuardExistingHint_OnConflict covers the three on_conflict modes on a
+// collision, and the fallback from the resource to the provider default.
+func TestGuardExistingHint_OnConflict(t *testing.T) {
+	cases := []struct {
+		name, resource, provider string
+		wantAdopted, wantErr     bool
+		wantDeletes, wantUpdates int32
+	}{
+		{name: "default", wantErr: true},
+		{name: "error", resource: onConflictError, provider: onConflictAdopt, wantErr: true},
+		{name: "adopt", resource: onConflictAdopt, wantAdopted: true, wantUpdates: 1},
+		{name: "provider adopt", provider: onConflictAdopt, wantAdopted: true, wantUpdates: 1},
+		{name: "replace", resource: onConflictReplace, wantDeletes: 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw := map[string]any{
+				"action": []any{map[string]any{
+					"type":  "iequal",
+					"value": "conflict.example.com",
+					"point": map[string]any{"header": "HOST"},
+				}},
+				"client_id": 1,
+			}
+			if tc.resource != "" {
+				raw["on_conflict"] = tc.resource
+			}
+			d := schema.TestResourceDataRaw(t, resourceWallarmAPIAbuseMode().Schema, raw)
+			d.MarkNewResource()
+			conditions, err := resourcerule.ExpandSetToActionDetailsList(d.Get("action").(*schema.Set))
+			if err != nil {
+				t.Fatalf("ExpandSetToActionDetailsList: %v", err)
+			}
+			mock := &mockHintAPI{
+				actions: []wallarm.ActionEntry{{ID: 42, Clientid: 1, Conditions: conditions}},
+				hints:   []wallarm.ActionBody{{ID: 100, ActionID: 42, Clientid: 1, Type: ruleTypeAPIAbuseMode}},
+			}
+			meta := &ProviderMeta{Client: mock, DefaultClientID: 1, OnConflict: tc.provider}
+
+			var updates atomic.Int32
+			update := func(_ context.Context, d *schema.ResourceData, _ any) diag.Diagnostics {
+				updates.Add(1)
+				if got := d.Get("rule_id").(int); got != 100 {
+					t.Errorf("update called with rule_id %d, want 100", got)
+				}
+				return nil
+			}
+
+			adopted, diags := guardExistingHint(context.Background(), d, meta,
+				ruleTypeAPIAbuseMode, "wallarm_rule_api_abuse_mode", nil, update)
+			if diags.HasError() != tc.wantErr {
+				t.Fatalf("HasError = %v, want %v: %v", diags.HasError(), tc.wantErr, diags)
+			}
+			if adopted != tc.wantAdopted {
+				t.Errorf("adopted = %v, want %v", adopted, tc.wantAdopted)
+			}
+			if got := mock.deleteCallCount.Load(); got != tc.wantDeletes {
+				t.Errorf("HintDelete calls = %d, want %d", got, tc.wantDeletes)
+			}
+			if got := updates.Load(); got != tc.wantUpdates {
+				t.Errorf("update calls = %d, want %d", got, tc.wantUpdates)
+			}
+			if tc.wantErr {
+				return
+			}
+			if len(diags) != 1 || diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Detail, `"1/42/100"`) {
+				t.Errorf("expected one warning naming import ID 1/42/100, got %+v", diags)
+			}
+			if tc.wantAdopted && d.Id() != "1/42/100" {
+				t.Errorf("ID = %q, want 1/42/100", d.Id())
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package wallarm
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/wallarm/terraform-provider-wallarm/wallarm/common/resourcerule"
+	wallarm "github.com/wallarm/wallarm-go"
+)
+
+// actionIndexPageCap bounds ActionList pagination while loading one scope of
+// the index, like findActionByConditionsHashPageCap.
+const actionIndexPageCap = findActionByConditionsHashPageCap
+
+// actionIndexScope is one ActionList query the index holds the result of:
+// the actions of a client that carry a hint type, or all actions of a client
+// split by whether they have conditions (hintType == "").
+type actionIndexScope struct {
+	clientID int
+	hintType string
+	empty    bool
+}
+
+// ActionIndex maps ConditionsHash to the action with those conditions. Each
+// scope is paginated from ActionList once per provider run, on first use;
+// HintCreate adds the actions it creates. It replaces a full ActionList
+// pagination per lookup in the duplicate-rule guard and wallarm_action.
+//
+// mu only guards the scope map. A scope loads under its own lock, so a slow
+// load on a large tenant blocks lookups in that scope only.
+//
+// Entries are never removed: an action outlives its hints, and callers
+// confirm hints on the matched action with HintRead.
+type ActionIndex struct {
+	mu     sync.Mutex
+	scopes map[actionIndexScope]*actionIndexScopeState
+}
+
+// actionIndexScopeState holds one scope. byHash is nil until the scope loads;
+// a failed load leaves it nil, so the next lookup retries.
+type actionIndexScopeState struct {
+	mu     sync.Mutex
+	byHash map[string]*wallarm.ActionEntry
+}
+
+// NewActionIndex creates an empty ActionIndex.
+func NewActionIndex() *ActionIndex {
+	return &ActionIndex{scopes: make(map[actionIndexScope]*actionIndexScopeState)}
+}
+
+// scope returns the state of a scope, creating it (unloaded) if needed.
+func (x *ActionIndex) scope(scope actionIndexScope) *actionIndexScopeState {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	st, ok := x.scopes[scope]
+	if !ok {
+		st = &actionIndexScopeState{}
+		x.scopes[scope] = st
+	}
+	return st
+}
+
+// existingScopes returns the states of those of scopes that exist, or of all
+// scopes if none are given.
+func (x *ActionIndex) existingScopes(scopes ...actionIndexScope) []*actionIndexScopeState {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	var states []*actionIndexScopeState
+	if len(scopes) == 0 {
+		for _, st := range x.scopes {
+			states = append(states, st)
+		}
+		return states
+	}
+	for _, scope := range scopes {
+		if st, ok := x.scopes[scope]; ok {
+			states = append(states, st)
+		}
+	}
+	return states
+}
+
+// Find returns the action of the scope whose conditions hash to wantHash, or
+// nil. The scope is loaded through api on first use; concurrent lookups in the
+// same scope wait for that load, other scopes are not blocked.
+func (x *ActionIndex) Find(api wallarm.API, scope actionIndexScope, wantHash string) (*wallarm.ActionEntry, error) {
+	st := x.scope(scope)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.byHash == nil {
+		byHash, err := loadActionIndexScope(api, scope)
+		if err != nil {
+			return nil, err
+		}
+		st.byHash = byHash
+	}
+	return st.byHash[wantHash], nil
+}
+
+// Add records an action created with a hint of hintType in the scopes that
+// are already loaded. Scopes loaded later get it from the API.
+func (x *ActionIndex) Add(clientID int, hintType string, entry *wallarm.ActionEntry) {
+	hash := resourcerule.ConditionsHash(entry.Conditions)
+	for _, st := range x.existingScopes(
+		actionIndexScope{clientID: clientID, hintType: hintType},
+		actionIndexScope{clientID: clientID, empty: len(entry.Conditions) == 0},
+	) {
+		st.mu.Lock()
+		if st.byHash != nil {
+			st.byHash[hash] = entry
+		}
+		st.mu.Unlock()
+	}
+}
+
+// Len returns the number of indexed actions over all loaded scopes.
+func (x *ActionIndex) Len() int {
+	n := 0
+	for _, st := range x.existingScopes() {
+		st.mu.Lock()
+		n += len(st.byHash)
+		st.mu.Unlock()
+	}
+	return n
+}
+
+func loadActionIndexScope(api wallarm.API, scope actionIndexScope) (map[string]*wallarm.ActionEntry, error) {
+	filter := &wallarm.ActionListFilter{Clientid: []int{scope.clientID}}
+	if scope.hintType != "" {
+		filter.HintType = []string{scope.hintType}
+	} else {
+		filter.Empty = &scope.empty
+	}
+	byHash := make(map[string]*wallarm.ActionEntry)
+	for page := 0; page < actionIndexPageCap; page++ {
+		resp, err := api.ActionList(&wallarm.ActionListParams{
+			Filter: filter,
+			Limit:  APIListLimit,
+			Offset: page * APIListLimit,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list actions: %w", err)
+		}
+		for i := range resp.Body {
+			hash := resourcerule.ConditionsHash(resp.Body[i].Conditions)
+			if _, dup := byHash[hash]; !dup {
+				byHash[hash] = &resp.Body[i]
+			}
+		}
+		if len(resp.Body) < APIListLimit {
+			log.Printf("[DEBUG] ActionIndex: loaded %d actions for client %d (hint type %q)", len(byHash), scope.clientID, scope.hintType)
+			return byHash, nil
+		}
+	}
+	return nil, fmt.Errorf("ActionIndex: pagination cap (%d pages × %d) exceeded for client %d, hint type %q without short page — possible API bug",
+		actionIndexPageCap, APIListLimit, scope.clientID, scope.hintType)
+}
@@ -0,0 +1,82 @@
+package wallarm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/wallarm/wallarm-go"
+)
+
+// rawAPI calls Wallarm API endpoints that wallarm-go does not wrap. It shares
+// the HTTP client (and so its auth transport) and base URL of the wallarm.API
+// client.
+type rawAPI struct {
+	httpClient *http.Client
+	baseURL    string
+	headers    http.Header
+	// readOnly refuses requests apiReadRequest does not know to be reads.
+	readOnly bool
+}
+
+func newRawAPI(httpClient *http.Client, baseURL string, authHeaders http.Header, userAgent string) *rawAPI {
+	headers := authHeaders.Clone()
+	headers.Set("User-Agent", userAgent)
+	return &rawAPI{httpClient: httpClient, baseURL: baseURL, headers: headers}
+}
+
+// do sends body as JSON and decodes the response into out (if not nil).
+// Non-2xx responses are returned as *wallarm.APIError, like wallarm-go does.
+func (r *rawAPI) do(ctx context.Context, method, uri string, body, out any) error {
+	if r.readOnly && !apiReadRequest(method, uri) {
+		return readOnlyError(method + " " + uri)
+	}
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(r.baseURL, "/")+uri, reqBody)
+	if err != nil {
+		return err
+	}
+	for k, v := range r.headers {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, uri, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return wallarm.NewAPIError(resp.StatusCode, string(respBody))
+	}
+	if out != nil && len(respBody) > 0 {
+		return json.Unmarshal(respBody, out)
+	}
+	return nil
+}
+
+// apiRaw extracts the rawAPI client from the provider meta.
+func apiRaw(m any) (*rawAPI, error) {
+	raw := m.(*ProviderMeta).RawAPI
+	if raw == nil {
+		return nil, fmt.Errorf("the provider is not configured for direct API calls")
+	}
+	return raw, nil
+}
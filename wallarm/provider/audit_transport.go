@@ -0,0 +1,332 @@
+package wallarm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// auditMaxErrorBody caps the response body kept for a failed mutation.
+const auditMaxErrorBody = 1024
+
+// auditEntry is one line of audit_log_path.
+type auditEntry struct {
+	Time       string          `json:"time"`
+	ClientID   int             `json:"client_id,omitempty"`
+	Operation  string          `json:"operation"` // "METHOD path"
+	ObjectIDs  []int           `json:"object_ids,omitempty"`
+	Request    json.RawMessage `json:"request,omitempty"`
+	Status     int             `json:"status,omitempty"`
+	DurationMS int64           `json:"duration_ms"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// auditLog is an append-only JSON-lines file. One per path and process, so
+// several provider configurations in one plugin process share it.
+type auditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+var (
+	auditLogsMu sync.Mutex
+	auditLogs   = map[string]*auditLog{}
+)
+
+func openAuditLog(path string) (*auditLog, error) {
+	auditLogsMu.Lock()
+	defer auditLogsMu.Unlock()
+	if l, ok := auditLogs[path]; ok {
+		return l, nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit_log_path: %w", err)
+	}
+	l := &auditLog{file: f}
+	auditLogs[path] = l
+	return l, nil
+}
+
+func (l *auditLog) write(e *auditEntry) {
+	line, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("[WARN] Wallarm audit log: %s", err)
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		log.Printf("[WARN] Wallarm audit log: %s", err)
+	}
+}
+
+// auditTransport is an http.RoundTripper that appends one auditLog entry per
+// mutating API request (anything apiReadRequest does not know to be a read),
+// including failed and retried attempts. Request bodies are stored with
+// secrets masked; responses only contribute their status and object IDs.
+type auditTransport struct {
+	transport http.RoundTripper
+	log       *auditLog
+	now       func() time.Time
+}
+
+func newAuditTransport(transport http.RoundTripper, l *auditLog) *auditTransport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &auditTransport{transport: transport, log: l, now: time.Now}
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if apiReadRequest(req.Method, req.URL.RequestURI()) {
+		return t.transport.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		reqBody, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	start := t.now()
+	resp, err := t.transport.RoundTrip(req)
+	entry := &auditEntry{
+		Time:       start.UTC().Format(time.RFC3339Nano),
+		Operation:  req.Method + " " + req.URL.Path,
+		DurationMS: t.now().Sub(start).Milliseconds(),
+	}
+
+	var reqJSON any
+	if len(reqBody) > 0 && json.Unmarshal(reqBody, &reqJSON) == nil {
+		entry.Request, _ = json.Marshal(maskAuditValue("", reqJSON))
+	}
+	entry.ClientID = auditClientID(req.URL.Path, reqJSON)
+	entry.ObjectIDs = auditPathIDs(req.URL.Path, entry.ClientID)
+	entry.ObjectIDs = appendAuditIDs(entry.ObjectIDs, auditFilterIDs(reqJSON)...)
+
+	if err != nil {
+		entry.Error = err.Error()
+		t.log.write(entry)
+		return resp, err
+	}
+	entry.Status = resp.StatusCode
+	if respBody, ok := readAuditResponse(resp); ok {
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			var respJSON any
+			if json.Unmarshal(respBody, &respJSON) == nil {
+				entry.ObjectIDs = appendAuditIDs(entry.ObjectIDs, auditResponseIDs(respJSON, 0)...)
+			}
+		} else {
+			entry.Error = truncate(string(respBody), auditMaxErrorBody)
+		}
+	}
+	t.log.write(entry)
+	return resp, nil
+}
+
+// readAuditResponse reads the response body and puts it back, decompressed
+// the way wallarm-go would see it.
+func readAuditResponse(resp *http.Response) ([]byte, bool) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil, false
+	}
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		return body, true
+	}
+	r, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, false
+	}
+	defer r.Close()
+	plain, err := io.ReadAll(r)
+	return plain, err == nil
+}
+
+// auditSecretKeyParts mark request fields whose values are masked: credentials
+// of integrations (API keys, tokens, passwords, webhook targets) and users.
+var auditSecretKeyParts = []string{
+	"token", "secret", "password", "key", "credential", "authorization",
+	"chat_data", "headers", "target",
+}
+
+func isAuditSecretKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, part := range auditSecretKeyParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskAuditValue masks secret fields of a decoded JSON request, including
+// everything nested under them, and cuts URLs down to scheme and host, since
+// webhook URLs carry their secret in the path.
+func maskAuditValue(key string, v any) any {
+	return maskSecretFields(key, v, true)
+}
+
+// maskSecretFields masks the values of secret fields and everything nested
+// under them; with cutURLs it also cuts other URLs down to scheme and host.
+func maskSecretFields(key string, v any, cutURLs bool) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			childKey := k
+			if isAuditSecretKey(key) {
+				childKey = key // keep masking below a secret field
+			}
+			out[k] = maskSecretFields(childKey, item, cutURLs)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = maskSecretFields(key, item, cutURLs)
+		}
+		return out
+	case string:
+		if isAuditSecretKey(key) {
+			return maskHTTPValue(val)
+		}
+		if !cutURLs {
+			return val
+		}
+		if u, err := url.Parse(val); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+			return u.Scheme + "://" + u.Hostname() + "/****"
+		}
+		return val
+	}
+	if isAuditSecretKey(key) && v != nil {
+		if _, ok := v.(bool); !ok {
+			return "****"
+		}
+	}
+	return v
+}
+
+var auditClientPath = regexp.MustCompile(`/clients?/(\d+)(?:/|$)`)
+
+// auditClientID returns the client a request targets, from its path
+// (/v2/clients/{id}/...) or its clientid/client_id field or filter.
+func auditClientID(path string, body any) int {
+	if m := auditClientPath.FindStringSubmatch(path); m != nil {
+		id, _ := strconv.Atoi(m[1])
+		return id
+	}
+	obj, _ := body.(map[string]any)
+	for _, o := range []any{obj, obj["filter"]} {
+		m, _ := o.(map[string]any)
+		for _, k := range []string{"clientid", "client_id"} {
+			if ids := auditInts(m[k]); len(ids) > 0 {
+				return ids[0]
+			}
+		}
+	}
+	return 0
+}
+
+// auditPathIDs returns the numeric path segments other than the client ID,
+// e.g. the rule of PUT /v3/hint/{id}.
+func auditPathIDs(path string, clientID int) []int {
+	var ids []int
+	for _, seg := range strings.Split(path, "/") {
+		if id, err := strconv.Atoi(seg); err == nil && id != clientID {
+			ids = appendAuditIDs(ids, id)
+		}
+	}
+	return ids
+}
+
+// auditFilterIDs returns filter.id of delete and update requests.
+func auditFilterIDs(body any) []int {
+	obj, _ := body.(map[string]any)
+	filter, _ := obj["filter"].(map[string]any)
+	return auditInts(filter["id"])
+}
+
+// auditResponseIDs returns the id fields of the objects a response returns:
+// {"body": {...}}, {"body": [...]}, {"body": {"object": {...}}} or
+// {"trigger": {...}}.
+func auditResponseIDs(v any, depth int) []int {
+	if depth > 3 {
+		return nil
+	}
+	var ids []int
+	switch val := v.(type) {
+	case map[string]any:
+		ids = appendAuditIDs(ids, auditInts(val["id"])...)
+		for _, k := range []string{"body", "object", "objects", "trigger"} {
+			if item, ok := val[k]; ok {
+				ids = appendAuditIDs(ids, auditResponseIDs(item, depth+1)...)
+			}
+		}
+	case []any:
+		for _, item := range val {
+			ids = appendAuditIDs(ids, auditResponseIDs(item, depth+1)...)
+		}
+	case float64:
+		if depth > 0 { // {"body": 123}, {"body": [1, 2]}
+			ids = appendAuditIDs(ids, int(val))
+		}
+	}
+	return ids
+}
+
+// auditInts reads a JSON number or list of numbers.
+func auditInts(v any) []int {
+	switch val := v.(type) {
+	case float64:
+		return []int{int(val)}
+	case []any:
+		var ids []int
+		for _, item := range val {
+			if n, ok := item.(float64); ok {
+				ids = append(ids, int(n))
+			}
+		}
+		return ids
+	}
+	return nil
+}
+
+func appendAuditIDs(ids []int, more ...int) []int {
+	for _, id := range more {
+		if id != 0 && !containsAuditID(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func containsAuditID(ids []int, id int) bool {
+	for _, x := range ids {
+		if x == id {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "…"
+}
@@ -0,0 +1,123 @@
+package wallarm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func readAuditLog(t *testing.T, path string) []auditEntry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var entries []auditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("audit line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestFakeAPI_AuditLog(t *testing.T) {
+	srv := testFakeAPIServer(t)
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	meta := testFakeAPIMeta(t, srv, map[string]any{"audit_log_path": path})
+	ctx := context.Background()
+	res := resourceWallarmMode()
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]any{
+		"mode": "monitoring",
+		"action": []any{map[string]any{
+			"type":  "iequal",
+			"value": "audit.example.com",
+			"point": map[string]any{"header": "HOST"},
+		}},
+	})
+	d.MarkNewResource()
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("Create: %v", diags)
+	}
+	ruleID := d.Get("rule_id").(int)
+	if diags := res.DeleteContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("Delete: %v", diags)
+	}
+
+	entries := readAuditLog(t, path)
+	var ops []string
+	for _, e := range entries {
+		ops = append(ops, e.Operation)
+	}
+	if want := "POST /v1/objects/hint/create POST /v1/objects/hint/delete"; strings.Join(ops, " ") != want {
+		t.Fatalf("audited operations = %v, want %s", ops, want)
+	}
+	for _, e := range entries {
+		if e.Time == "" || e.Status != 200 || e.ClientID != 1 || e.Error != "" || len(e.Request) == 0 {
+			t.Errorf("incomplete entry %+v", e)
+		}
+		if !containsAuditID(e.ObjectIDs, ruleID) {
+			t.Errorf("%s object_ids = %v, want rule %d", e.Operation, e.ObjectIDs, ruleID)
+		}
+	}
+}
+
+func TestAuditTransport_MasksSecrets(t *testing.T) {
+	body := map[string]any{
+		"name":     "hook",
+		"active":   true,
+		"clientid": float64(7),
+		"target":   "https://hooks.example.com/services/T000/B000/XXXXsecret",
+		"headers":  map[string]any{"X-Auth": "abcdefgh"},
+		"api_key":  "sk-1234567890",
+		"token":    float64(123456),
+		"events":   []any{map[string]any{"event": "hit", "active": true}},
+		"filter":   map[string]any{"url": "http://internal.example.com/path/with/secret"},
+	}
+	raw, _ := json.Marshal(maskAuditValue("", body))
+	masked := string(raw)
+	for _, leak := range []string{"XXXXsecret", "abcdefgh", "sk-1234567890", "123456", "/path/with"} {
+		if strings.Contains(masked, leak) {
+			t.Errorf("masked request %s leaks %q", masked, leak)
+		}
+	}
+	for _, keep := range []string{`"name":"hook"`, `"active":true`, `"clientid":7`, `"event":"hit"`, "http://internal.example.com/****"} {
+		if !strings.Contains(masked, keep) {
+			t.Errorf("masked request %s lost %s", masked, keep)
+		}
+	}
+}
+
+func TestAuditTransport_IDs(t *testing.T) {
+	var body any
+	_ = json.Unmarshal([]byte(`{"filter": {"clientid": [7], "id": [42, 43]}}`), &body)
+	if got := auditClientID("/v1/objects/hint/delete", body); got != 7 {
+		t.Errorf("client from filter = %d, want 7", got)
+	}
+	if got := auditFilterIDs(body); len(got) != 2 || got[0] != 42 {
+		t.Errorf("filter ids = %v", got)
+	}
+	if got := auditClientID("/v2/clients/7/rules/wallarm-mode", nil); got != 7 {
+		t.Errorf("client from path = %d, want 7", got)
+	}
+	if got := auditPathIDs("/v2/client/7/triggers/99", 7); len(got) != 1 || got[0] != 99 {
+		t.Errorf("path ids = %v, want [99]", got)
+	}
+
+	var resp any
+	_ = json.Unmarshal([]byte(`{"status": 200, "body": {"id": 5, "object": {"id": 6}, "objects": [{"id": 7}]}}`), &resp)
+	if got := auditResponseIDs(resp, 0); len(got) != 3 {
+		t.Errorf("response ids = %v, want [5 6 7]", got)
+	}
+}
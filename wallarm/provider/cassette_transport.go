@@ -0,0 +1,359 @@
+package wallarm
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+const (
+	// cassetteEnv names the cassette file; setting it enables the transport.
+	cassetteEnv = "WALLARM_API_CASSETTE"
+	// cassetteModeEnv is "record" or "replay" (the default).
+	cassetteModeEnv = "WALLARM_API_CASSETTE_MODE"
+
+	cassetteModeRecord = "record"
+	cassetteModeReplay = "replay"
+)
+
+// cassetteInteraction is one recorded request/response pair, stored as one
+// JSON line of the cassette file. Sensitive headers and secret JSON fields
+// are masked, like in audit_log_path; response bodies are stored
+// decompressed.
+type cassetteInteraction struct {
+	Method          string      `json:"method"`
+	URI             string      `json:"uri"` // path and query
+	RequestHeaders  http.Header `json:"request_headers,omitempty"`
+	RequestBody     string      `json:"request_body,omitempty"`
+	Status          int         `json:"status"`
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+	ResponseBody    string      `json:"response_body,omitempty"`
+}
+
+func (i *cassetteInteraction) key() string {
+	return cassetteKey(i.Method, i.URI, []byte(i.RequestBody))
+}
+
+// cassette is the state behind every cassetteTransport of a process that
+// uses the same file. Acceptance tests configure a new provider for each
+// step, so the recording and the replay position must outlive one
+// ProviderConfigure.
+type cassette struct {
+	path string
+	mode string
+
+	mu     sync.Mutex
+	file   *os.File                          // record
+	byKey  map[string][]*cassetteInteraction // replay
+	served map[string]int                    // replay
+}
+
+var (
+	cassettesMu sync.Mutex
+	cassettes   = make(map[string]*cassette)
+)
+
+// cassetteTransport is an http.RoundTripper that records API interactions to
+// a cassette file or replays them from one, so acceptance tests can run
+// without network access or credentials.
+//
+// A replayed request is matched on method, path with query and normalized
+// JSON body, ignoring the values of time-derived fields; headers and host are
+// ignored. Interactions with the same key are
+// served in recorded order, and the last one is repeated once they run out,
+// which absorbs the extra refreshes a different Terraform version may do.
+type cassetteTransport struct {
+	cassette  *cassette
+	transport http.RoundTripper
+}
+
+// cassetteTransportFromEnv wraps transport in a cassetteTransport when
+// WALLARM_API_CASSETTE is set and returns it unchanged otherwise. In replay
+// mode transport is never called.
+func cassetteTransportFromEnv(transport http.RoundTripper) (http.RoundTripper, error) {
+	path := os.Getenv(cassetteEnv)
+	if path == "" {
+		return transport, nil
+	}
+	c, err := openCassette(path, os.Getenv(cassetteModeEnv))
+	if err != nil {
+		return nil, err
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &cassetteTransport{cassette: c, transport: transport}, nil
+}
+
+// cassetteReplayEnabled reports whether API calls are served from a cassette.
+func cassetteReplayEnabled() bool {
+	mode := os.Getenv(cassetteModeEnv)
+	return os.Getenv(cassetteEnv) != "" && (mode == "" || mode == cassetteModeReplay)
+}
+
+func openCassette(path, mode string) (*cassette, error) {
+	if mode == "" {
+		mode = cassetteModeReplay
+	}
+	if mode != cassetteModeRecord && mode != cassetteModeReplay {
+		return nil, fmt.Errorf("%s must be %q or %q, got %q", cassetteModeEnv, cassetteModeRecord, cassetteModeReplay, mode)
+	}
+
+	cassettesMu.Lock()
+	defer cassettesMu.Unlock()
+	if c, ok := cassettes[path]; ok {
+		if c.mode != mode {
+			return nil, fmt.Errorf("cassette %s is already open in %s mode", path, c.mode)
+		}
+		return c, nil
+	}
+
+	c := &cassette{path: path, mode: mode}
+	if mode == cassetteModeRecord {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create cassette directory: %w", err)
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cassette: %w", err)
+		}
+		c.file = f
+		log.Printf("[INFO] Recording Wallarm API interactions to cassette %s", path)
+	} else {
+		if err := c.load(); err != nil {
+			return nil, err
+		}
+		log.Printf("[INFO] Replaying Wallarm API interactions from cassette %s", path)
+	}
+	cassettes[path] = c
+	return c, nil
+}
+
+func (c *cassette) load() error {
+	f, err := os.Open(c.path)
+	if err != nil {
+		return fmt.Errorf("failed to open cassette: %w", err)
+	}
+	defer f.Close()
+
+	c.byKey = make(map[string][]*cassetteInteraction)
+	c.served = make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var i cassetteInteraction
+		if err := json.Unmarshal(scanner.Bytes(), &i); err != nil {
+			return fmt.Errorf("cassette %s line %d: %w", c.path, line, err)
+		}
+		key := i.key()
+		c.byKey[key] = append(c.byKey[key], &i)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read cassette: %w", err)
+	}
+	return nil
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	if t.cassette.mode == cassetteModeReplay {
+		return t.cassette.replay(req, reqBody)
+	}
+	return t.record(req, reqBody)
+}
+
+func (t *cassetteTransport) record(req *http.Request, reqBody []byte) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store the body decompressed so cassettes stay readable and diffable.
+	var body io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		reader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("gzip decompression failed: %w", err)
+		}
+		defer reader.Close()
+		body = reader
+		resp.Header.Del("Content-Encoding")
+	}
+	respBody, err := io.ReadAll(body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	resp.ContentLength = int64(len(respBody))
+	resp.Header.Del("Content-Length")
+
+	interaction := &cassetteInteraction{
+		Method:          req.Method,
+		URI:             req.URL.RequestURI(),
+		RequestHeaders:  maskCassetteHeaders(req.Header),
+		RequestBody:     string(maskCassetteBody(reqBody, true)),
+		Status:          resp.StatusCode,
+		ResponseHeaders: maskCassetteHeaders(resp.Header),
+		ResponseBody:    string(maskCassetteBody(respBody, false)),
+	}
+	if err := t.cassette.append(interaction); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// append writes one interaction as a JSON line, so a cassette is usable even
+// if the test run is interrupted.
+func (c *cassette) append(i *cassetteInteraction) error {
+	line, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+func (c *cassette) replay(req *http.Request, reqBody []byte) (*http.Response, error) {
+	key := cassetteKey(req.Method, req.URL.RequestURI(), reqBody)
+	c.mu.Lock()
+	recorded := c.byKey[key]
+	if len(recorded) == 0 {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("cassette %s has no interaction for %s %s with body %s",
+			c.path, req.Method, req.URL.RequestURI(), normalizeCassetteBody(maskCassetteBody(reqBody, true)))
+	}
+	n := c.served[key]
+	c.served[key] = n + 1
+	c.mu.Unlock()
+
+	i := recorded[min(n, len(recorded)-1)]
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+		StatusCode:    i.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        i.ResponseHeaders.Clone(),
+		Body:          io.NopCloser(bytes.NewReader([]byte(i.ResponseBody))),
+		ContentLength: int64(len(i.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+// cassetteKey masks body the way record stores it, so a live request matches
+// its masked recording.
+func cassetteKey(method, uri string, body []byte) string {
+	return method + " " + uri + " " + normalizeCassetteBody(maskCassetteBody(body, true))
+}
+
+func maskCassetteHeaders(header http.Header) http.Header {
+	masked := make(http.Header, len(header))
+	for key, vals := range header {
+		for _, val := range vals {
+			if isSensitiveHTTPHeader(key) {
+				val = maskHTTPValue(val)
+			}
+			masked.Add(key, val)
+		}
+	}
+	return masked
+}
+
+// maskCassetteBody masks the secret fields of a JSON body with
+// maskSecretFields. Bodies without secrets, and bodies that are not JSON, are
+// returned as is. Responses are recorded without cutURLs, so a replay returns
+// the data the API did apart from the secrets.
+func maskCassetteBody(body []byte, cutURLs bool) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+	masked := maskSecretFields("", v, cutURLs)
+	if reflect.DeepEqual(masked, v) {
+		return body
+	}
+	out, err := json.Marshal(masked)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+// cassetteVolatileFields are request fields the provider derives from the
+// current time: expired_at of IP list entries with a relative expiry and the
+// updated_at window of the hint delta query. Matching ignores their values,
+// which differ between recording and replay.
+var cassetteVolatileFields = []string{"expired_at", "updated_at"}
+
+// normalizeCassetteBody re-encodes a JSON body with sorted object keys, so
+// the order wallarm-go marshals map fields in does not affect matching, and
+// with cassetteVolatileFields blanked. Bodies that are not JSON are compared
+// as is.
+func normalizeCassetteBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return string(body)
+	}
+	normalized, err := json.Marshal(blankCassetteVolatileFields(v))
+	if err != nil {
+		return string(body)
+	}
+	return string(normalized)
+}
+
+// blankCassetteVolatileFields replaces the value of every
+// cassetteVolatileFields key in v, at any depth, with a placeholder.
+func blankCassetteVolatileFields(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			if slices.Contains(cassetteVolatileFields, k) {
+				v[k] = "<volatile>"
+			} else {
+				v[k] = blankCassetteVolatileFields(val)
+			}
+		}
+	case []any:
+		for i, val := range v {
+			v[i] = blankCassetteVolatileFields(val)
+		}
+	}
+	return v
+}
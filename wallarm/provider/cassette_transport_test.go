@@ -0,0 +1,192 @@
+package wallarm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func cassetteDo(t *testing.T, transport http.RoundTripper, method, url, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-WallarmAPI-Token", "secret-token-1234")
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(b)
+}
+
+func TestCassetteTransport_RecordAndReplay(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		if r.URL.Path == "/v1/objects/hint" {
+			// Gzip like the API, to check the cassette stores plain bodies.
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			defer gz.Close()
+			io.WriteString(gz, `{"body": [{"id": `+string('0'+n)+`}]}`)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	t.Setenv(cassetteEnv, path)
+	t.Setenv(cassetteModeEnv, cassetteModeRecord)
+	recorder, err := cassetteTransportFromEnv(srv.Client().Transport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, body := cassetteDo(t, recorder, "POST", srv.URL+"/v1/objects/hint", `{"limit": 1, "filter": {"clientid": [1]}}`); body != `{"body": [{"id": 1}]}` {
+		t.Fatalf("recorded body = %q", body)
+	}
+	cassetteDo(t, recorder, "POST", srv.URL+"/v1/objects/hint", `{"limit": 1, "filter": {"clientid": [1]}}`)
+	cassetteDo(t, recorder, "GET", srv.URL+"/v1/missing?b=2", "")
+
+	recorded, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(recorded, []byte("secret-token")) || !bytes.Contains(recorded, []byte("*1234")) {
+		t.Errorf("token not masked in cassette:\n%s", recorded)
+	}
+
+	// Replay from a fresh process state, against no server at all.
+	srv.Close()
+	delete(cassettes, path)
+	t.Setenv(cassetteModeEnv, cassetteModeReplay)
+	replayer, err := cassetteTransportFromEnv(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Key order and whitespace differ from the recording.
+	body := `{"filter":{"clientid":[1]},"limit":1}`
+	for _, want := range []string{`{"body": [{"id": 1}]}`, `{"body": [{"id": 2}]}`, `{"body": [{"id": 2}]}`} {
+		if status, got := cassetteDo(t, replayer, "POST", "https://api.wallarm.com/v1/objects/hint", body); status != http.StatusOK || got != want {
+			t.Errorf("replay = %d %q, want 200 %q", status, got, want)
+		}
+	}
+	if status, _ := cassetteDo(t, replayer, "GET", "https://api.wallarm.com/v1/missing?b=2", ""); status != http.StatusNotFound {
+		t.Errorf("replayed status = %d, want 404", status)
+	}
+	req, _ := http.NewRequest("POST", "https://api.wallarm.com/v1/objects/hint", strings.NewReader(`{"limit": 2}`))
+	if _, err := replayer.RoundTrip(req); err == nil || !strings.Contains(err.Error(), "no interaction") {
+		t.Errorf("unmatched request: got %v, want a no interaction error", err)
+	}
+}
+
+func TestCassetteTransport_ReplaysTimeDerivedFields(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		io.WriteString(w, `{"status": 200}`)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	t.Setenv(cassetteEnv, path)
+	t.Setenv(cassetteModeEnv, cassetteModeRecord)
+	recorder, err := cassetteTransportFromEnv(srv.Client().Transport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An IP list create with a relative expiry, as recorded an hour earlier.
+	const create = `{"list":"block","force":false,"reason":"r","application_ids":[],"expired_at":%d,"rules":[{"rules_type":"ip_range","values":["1.2.3.4"]}]}`
+	recordedAt := time.Now().Add(-time.Hour)
+	cassetteDo(t, recorder, "POST", srv.URL+"/v1/blocklist/clients/1/access_rules",
+		fmt.Sprintf(create, recordedAt.Add(24*time.Hour).Unix()))
+
+	srv.Close()
+	delete(cassettes, path)
+	t.Setenv(cassetteModeEnv, cassetteModeReplay)
+	replayer, err := cassetteTransportFromEnv(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := cassetteDo(t, replayer, "POST", "https://api.wallarm.com/v1/blocklist/clients/1/access_rules",
+		fmt.Sprintf(create, time.Now().Add(24*time.Hour).Unix())); status != http.StatusOK {
+		t.Errorf("replayed status = %d, want 200", status)
+	}
+	// Other fields still have to match.
+	req, _ := http.NewRequest("POST", "https://api.wallarm.com/v1/blocklist/clients/1/access_rules",
+		strings.NewReader(strings.Replace(fmt.Sprintf(create, 1), "1.2.3.4", "5.6.7.8", 1)))
+	if _, err := replayer.RoundTrip(req); err == nil {
+		t.Error("a request with different values matched the recording")
+	}
+}
+
+func TestCassetteTransport_MasksSecrets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Set-Cookie", "session=cookie-secret-5678")
+		io.WriteString(w, `{"body": {"id": 3, "target": "https://hooks.example.com/T000/XXXXsecret", "url": "https://app.example.com/page"}}`)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	t.Setenv(cassetteEnv, path)
+	t.Setenv(cassetteModeEnv, cassetteModeRecord)
+	recorder, err := cassetteTransportFromEnv(srv.Client().Transport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqBody := `{"name": "hook", "api_key": "sk-1234567890", "url": "https://app.example.com/page"}`
+	cassetteDo(t, recorder, "POST", srv.URL+"/v1/integrations", reqBody)
+
+	recorded, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range []string{"sk-1234567890", "XXXXsecret", "cookie-secret", "/T000"} {
+		if bytes.Contains(recorded, []byte(leak)) {
+			t.Errorf("cassette leaks %q:\n%s", leak, recorded)
+		}
+	}
+	// Non-secret response fields are replayed as recorded.
+	if !bytes.Contains(recorded, []byte("https://app.example.com/page")) {
+		t.Errorf("cassette lost the response url:\n%s", recorded)
+	}
+
+	srv.Close()
+	delete(cassettes, path)
+	t.Setenv(cassetteModeEnv, cassetteModeReplay)
+	replayer, err := cassetteTransportFromEnv(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, got := cassetteDo(t, replayer, "POST", "https://api.wallarm.com/v1/integrations", reqBody); status != http.StatusOK || !strings.Contains(got, `"id":3`) {
+		t.Errorf("replay of the unmasked request = %d %q", status, got)
+	}
+}
+
+func TestCassetteTransportFromEnv(t *testing.T) {
+	t.Setenv(cassetteEnv, "")
+	base := http.DefaultTransport
+	if got, err := cassetteTransportFromEnv(base); err != nil || got != base {
+		t.Errorf("without %s: got %v, %v; want the transport unchanged", cassetteEnv, got, err)
+	}
+
+	t.Setenv(cassetteEnv, filepath.Join(t.TempDir(), "c.jsonl"))
+	t.Setenv(cassetteModeEnv, "rewind")
+	if _, err := cassetteTransportFromEnv(base); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+	t.Setenv(cassetteModeEnv, "")
+	if _, err := cassetteTransportFromEnv(base); err == nil {
+		t.Error("expected an error replaying a missing cassette")
+	}
+}
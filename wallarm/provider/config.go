@@ -18,6 +18,10 @@ type ProviderMeta struct {
 	RequireExplicitClientID bool
 	IPListCache             *IPListCache
 	CredentialStuffingCache *CredentialStuffingCache
+	// RawAPI calls endpoints wallarm-go does not wrap (e.g. integration tests).
+	RawAPI *rawAPI
+	// OnConflict is the default on_conflict of rule resources.
+	OnConflict string
 }
 
 // RetrieveClientID returns the client_id from the resource if set,
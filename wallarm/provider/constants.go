@@ -1,5 +1,7 @@
 package wallarm
 
+import "time"
+
 // API pagination and batch size limits.
 // All limits are centralized here to avoid scattering across files.
 const (
@@ -24,4 +26,7 @@ const (
 
 	// IPListCacheRetryDelay is the wait time between cache refresh retries.
 	IPListCacheRetryDelay = 3
+
+	// IPListExpiryWarningWindow is how far ahead of expiry Read warns that IP list entries are about to expire.
+	IPListExpiryWarningWindow = 24 * time.Hour
 )
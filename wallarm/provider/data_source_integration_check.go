@@ -0,0 +1,80 @@
+package wallarm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dataSourceWallarmIntegrationCheck sends a test event through an existing
+// integration on every read.
+func dataSourceWallarmIntegrationCheck() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceWallarmIntegrationCheckRead,
+
+		Schema: map[string]*schema.Schema{
+			"client_id": defaultClientIDWithValidationSchema,
+
+			"integration_id": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+
+			"fail_on_error": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"success": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"error": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"checked_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceWallarmIntegrationCheckRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	clientID, err := retrieveClientID(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	integrationID := d.Get("integration_id").(int)
+
+	checkErr := checkIntegration(ctx, m, clientID, integrationID)
+	if errors.Is(checkErr, errReadOnly) {
+		return diag.Errorf("integration %d was not tested: %s", integrationID, checkErr)
+	}
+	if checkErr != nil && isNotFoundError(checkErr) {
+		return diag.Errorf("integration %d not found for client %d", integrationID, clientID)
+	}
+	if checkErr != nil && d.Get("fail_on_error").(bool) {
+		return diag.Errorf("integration %d failed the connectivity test: %s", integrationID, checkErr)
+	}
+
+	errText := ""
+	if checkErr != nil {
+		errText = checkErr.Error()
+	}
+	d.Set("success", checkErr == nil)
+	d.Set("error", errText)
+	d.Set("checked_at", time.Now().UTC().Format(time.RFC3339))
+	d.SetId(fmt.Sprintf("integration_check_%d_%d", clientID, integrationID))
+	return nil
+}
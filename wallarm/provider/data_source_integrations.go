@@ -0,0 +1,195 @@
+package wallarm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/wallarm/wallarm-go"
+)
+
+func dataSourceWallarmIntegrations() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceWallarmIntegrationsRead,
+
+		Schema: map[string]*schema.Schema{
+			"client_id": defaultClientIDWithValidationSchema,
+
+			"type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filter by API integration type, e.g. slack, web_hooks, pager_duty.",
+			},
+
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filter by exact integration name.",
+			},
+
+			"active": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Filter by integration status. Returns active and disabled integrations if omitted.",
+			},
+
+			"integrations": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"integration_id": {Type: schema.TypeInt, Computed: true},
+						"client_id":      {Type: schema.TypeInt, Computed: true},
+						"name":           {Type: schema.TypeString, Computed: true},
+						"type":           {Type: schema.TypeString, Computed: true},
+						"active":         {Type: schema.TypeBool, Computed: true},
+						"created_by":     {Type: schema.TypeString, Computed: true},
+						"created_at":     {Type: schema.TypeInt, Computed: true},
+						"events": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"event_type":   {Type: schema.TypeString, Computed: true},
+									"active":       {Type: schema.TypeBool, Computed: true},
+									"with_headers": {Type: schema.TypeBool, Computed: true},
+								},
+							},
+						},
+						"target_json": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Sensitive:   true,
+							Description: "Integration target as returned by the API, as JSON. Secrets are usually masked.",
+						},
+						"terraform_resource": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Terraform resource name, e.g. wallarm_integration_slack; wallarm_integration for types without a dedicated resource.",
+						},
+						"import_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Pre-computed import ID for use in import blocks",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// integrationFilter selects integrations in data.wallarm_integrations. Active
+// is nil when the status is not filtered on.
+type integrationFilter struct {
+	Type   string
+	Name   string
+	Active *bool
+}
+
+func (f integrationFilter) match(obj *wallarm.IntegrationObject) bool {
+	return (f.Type == "" || obj.Type == f.Type) &&
+		(f.Name == "" || obj.Name == f.Name) &&
+		(f.Active == nil || obj.Active == *f.Active)
+}
+
+// listIntegrations returns every integration of the client. wallarm-go only
+// exposes a lookup by ID, so the list is fetched through rawAPI.
+func listIntegrations(ctx context.Context, m any, clientID int) ([]wallarm.IntegrationObject, error) {
+	raw, err := apiRaw(m)
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	q.Add("clientid", strconv.Itoa(clientID))
+	var resp wallarm.IntegrationRead
+	if err := raw.do(ctx, "GET", "/v2/integration?"+q.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Body.Object == nil {
+		return nil, nil
+	}
+	return *resp.Body.Object, nil
+}
+
+// integrationTerraformResource returns the resource that manages integrations
+// of an API type.
+func integrationTerraformResource(apiType string) string {
+	for name, spec := range integrationSpecs {
+		if spec.APIType == apiType {
+			return name
+		}
+	}
+	return "wallarm_integration"
+}
+
+func flattenIntegrationInventory(obj *wallarm.IntegrationObject, clientID int) (map[string]any, error) {
+	target, err := json.Marshal(obj.Target)
+	if err != nil {
+		return nil, fmt.Errorf("integration %d: %w", obj.ID, err)
+	}
+	events := make([]any, 0, len(obj.Events))
+	for _, e := range obj.Events {
+		events = append(events, map[string]any{
+			"event_type":   e.Event,
+			"active":       e.Active,
+			"with_headers": e.WithHeaders != nil && *e.WithHeaders,
+		})
+	}
+	return map[string]any{
+		"integration_id":     obj.ID,
+		"client_id":          clientID,
+		"name":               obj.Name,
+		"type":               obj.Type,
+		"active":             obj.Active,
+		"created_by":         obj.CreatedBy,
+		"created_at":         obj.CreatedAt,
+		"events":             events,
+		"target_json":        string(target),
+		"terraform_resource": integrationTerraformResource(obj.Type),
+		"import_id":          fmt.Sprintf("%d/%s/%d", clientID, obj.Type, obj.ID),
+	}, nil
+}
+
+func dataSourceWallarmIntegrationsRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	clientID, err := retrieveClientID(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	filter := integrationFilter{
+		Type: d.Get("type").(string),
+		Name: d.Get("name").(string),
+	}
+	if isConfigured(d, "active") {
+		active := d.Get("active").(bool)
+		filter.Active = &active
+	}
+
+	objs, err := listIntegrations(ctx, m, clientID)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to list integrations for client %d: %w", clientID, err))
+	}
+
+	integrations := make([]any, 0, len(objs))
+	for i := range objs {
+		if !filter.match(&objs[i]) {
+			continue
+		}
+		entry, err := flattenIntegrationInventory(&objs[i], clientID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		integrations = append(integrations, entry)
+	}
+
+	d.SetId(fmt.Sprintf("integrations_%d", clientID))
+	if err := d.Set("integrations", integrations); err != nil {
+		return diag.FromErr(fmt.Errorf("error setting integrations: %w", err))
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package wallarm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/wallarm/wallarm-go"
+)
+
+func dataSourceWallarmTriggers() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceWallarmTriggersRead,
+
+		Schema: map[string]*schema.Schema{
+			"client_id": defaultClientIDWithValidationSchema,
+
+			"template_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filter by trigger template, e.g. attacks_exceeded.",
+			},
+
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filter by exact trigger name.",
+			},
+
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Filter by trigger status. Returns enabled and disabled triggers if omitted.",
+			},
+
+			"triggers": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"trigger_id":  {Type: schema.TypeInt, Computed: true},
+						"client_id":   {Type: schema.TypeInt, Computed: true},
+						"template_id": {Type: schema.TypeString, Computed: true},
+						"name":        {Type: schema.TypeString, Computed: true},
+						"comment":     {Type: schema.TypeString, Computed: true},
+						"enabled":     {Type: schema.TypeBool, Computed: true},
+						"filters": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"filter_id": {Type: schema.TypeString, Computed: true},
+									"operator":  {Type: schema.TypeString, Computed: true},
+									"value": {
+										Type:     schema.TypeList,
+										Computed: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+						"actions": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Action IDs. See the wallarm_trigger resource for action params (integration_id, lock_time).",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"threshold": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"count":    {Type: schema.TypeInt, Computed: true},
+									"period":   {Type: schema.TypeInt, Computed: true},
+									"operator": {Type: schema.TypeString, Computed: true},
+								},
+							},
+						},
+						"import_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Pre-computed import ID for use in import blocks",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// triggerFilter selects triggers in data.wallarm_triggers. Enabled is nil
+// when the status is not filtered on.
+type triggerFilter struct {
+	TemplateID string
+	Name       string
+	Enabled    *bool
+}
+
+func (f triggerFilter) match(t *wallarm.TriggerResp) bool {
+	return (f.TemplateID == "" || t.Template.ID == f.TemplateID) &&
+		(f.Name == "" || t.Name == f.Name) &&
+		(f.Enabled == nil || t.Enabled == *f.Enabled)
+}
+
+func flattenTriggerInventory(t *wallarm.TriggerResp, clientID int) map[string]any {
+	actions := make([]any, 0, len(t.Actions))
+	for _, a := range t.Actions {
+		actions = append(actions, a.ID)
+	}
+	// The threshold period is reported in seconds.
+	threshold := flattenWallarmTriggerThreshold(t, nil)
+	for _, th := range threshold {
+		delete(th.(map[string]any), "time_format")
+	}
+	comment, _ := t.Comment.(string)
+	return map[string]any{
+		"trigger_id":  t.ID,
+		"client_id":   clientID,
+		"template_id": t.Template.ID,
+		"name":        t.Name,
+		"comment":     comment,
+		"enabled":     t.Enabled,
+		"filters":     flattenWallarmTriggerFilters(t.Filters),
+		"actions":     actions,
+		"threshold":   threshold,
+		"import_id":   fmt.Sprintf("%d/%s/%d", clientID, t.Template.ID, t.ID),
+	}
+}
+
+func dataSourceWallarmTriggersRead(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := apiClient(m)
+	clientID, err := retrieveClientID(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	filter := triggerFilter{
+		TemplateID: d.Get("template_id").(string),
+		Name:       d.Get("name").(string),
+	}
+	if isConfigured(d, "enabled") {
+		enabled := d.Get("enabled").(bool)
+		filter.Enabled = &enabled
+	}
+
+	resp, err := client.TriggerRead(clientID)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to read triggers for client %d: %w", clientID, err))
+	}
+
+	triggers := make([]any, 0, len(resp.Triggers))
+	for i := range resp.Triggers {
+		if filter.match(&resp.Triggers[i]) {
+			triggers = append(triggers, flattenTriggerInventory(&resp.Triggers[i], clientID))
+		}
+	}
+
+	d.SetId(fmt.Sprintf("triggers_%d", clientID))
+	if err := d.Set("triggers", triggers); err != nil {
+		return diag.FromErr(fmt.Errorf("error setting triggers: %w", err))
+	}
+	return nil
+}
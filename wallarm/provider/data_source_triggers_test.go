@@ -0,0 +1,110 @@
+package wallarm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	wallarm "github.com/wallarm/wallarm-go"
+)
+
+func TestTriggersDataSource_Read(t *testing.T) {
+	meta := &ProviderMeta{Client: &mockTriggerAPI{payload: testTriggerPayload}, DefaultClientID: 8649}
+	r := dataSourceWallarmTriggers()
+	d := r.TestResourceData()
+	if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if n := d.Get("triggers.#"); n != 1 {
+		t.Fatalf("triggers: got %v, want 1", n)
+	}
+	for key, want := range map[string]any{
+		"triggers.0.trigger_id":           77,
+		"triggers.0.template_id":          "bruteforce_started",
+		"triggers.0.name":                 "Brute",
+		"triggers.0.comment":              "",
+		"triggers.0.enabled":              false,
+		"triggers.0.import_id":            "8649/bruteforce_started/77",
+		"triggers.0.filters.1.filter_id":  "pool",
+		"triggers.0.threshold.0.period":   1800,
+		"triggers.0.threshold.0.count":    30,
+		"triggers.0.threshold.0.operator": "gt",
+	} {
+		if got := d.Get(key); got != want {
+			t.Errorf("%s: got %v, want %v", key, got, want)
+		}
+	}
+	if got := d.Get("triggers.0.actions"); !reflect.DeepEqual(got, []any{"mark_as_brute", "block_ips"}) {
+		t.Errorf("actions: got %v", got)
+	}
+}
+
+func TestTriggerFilter_Match(t *testing.T) {
+	enabled, disabled := true, false
+	trigger := &wallarm.TriggerResp{Name: "Brute", Enabled: false}
+	trigger.Template.ID = "bruteforce_started"
+	for _, tc := range []struct {
+		filter triggerFilter
+		want   bool
+	}{
+		{triggerFilter{}, true},
+		{triggerFilter{TemplateID: "bruteforce_started", Name: "Brute", Enabled: &disabled}, true},
+		{triggerFilter{TemplateID: "attacks_exceeded"}, false},
+		{triggerFilter{Name: "brute"}, false},
+		{triggerFilter{Enabled: &enabled}, false},
+	} {
+		if got := tc.filter.match(trigger); got != tc.want {
+			t.Errorf("%+v: got %v, want %v", tc.filter, got, tc.want)
+		}
+	}
+}
+
+func TestAccDataSourceTriggers(t *testing.T) {
+	rnd := generateRandomResourceName(10)
+	name := "wallarm_trigger." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceTriggersConfig(rnd),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.wallarm_triggers.by_name", "triggers.#", "1"),
+					resource.TestCheckResourceAttrPair("data.wallarm_triggers.by_name", "triggers.0.trigger_id", name, "trigger_id"),
+					resource.TestCheckResourceAttr("data.wallarm_triggers.by_name", "triggers.0.template_id", "user_created"),
+					resource.TestCheckResourceAttr("data.wallarm_triggers.by_name", "triggers.0.actions.0", "send_notification"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceTriggersConfig(resourceID string) string {
+	return fmt.Sprintf(`
+resource "wallarm_integration_email" "%[1]s" {
+	emails = ["%[1]s@wallarm.com"]
+
+	event {
+		event_type = "system"
+		active = true
+	}
+}
+
+resource "wallarm_trigger" "%[1]s" {
+	name = "tf-test-%[1]s"
+	template_id = "user_created"
+	actions {
+		action_id = "send_notification"
+		integration_id = [wallarm_integration_email.%[1]s.integration_id]
+	}
+}
+
+data "wallarm_triggers" "by_name" {
+	template_id = "user_created"
+	name = wallarm_trigger.%[1]s.name
+}`, resourceID)
+}
@@ -0,0 +1,200 @@
+package wallarm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/wallarm/terraform-provider-wallarm/wallarm/fakeapi"
+)
+
+// testFakeAPIServer starts a fake API server for the duration of the test.
+func testFakeAPIServer(t *testing.T) *fakeapi.Server {
+	t.Helper()
+	t.Setenv(cassetteEnv, "")
+	srv := fakeapi.New()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// testFakeAPIMeta configures the provider against srv, as one Terraform run
+// would. Extra provider settings override the defaults.
+func testFakeAPIMeta(t *testing.T, srv *fakeapi.Server, extra map[string]any) any {
+	t.Helper()
+	raw := map[string]any{
+		"api_host":    srv.URL,
+		"allow_http":  true,
+		"api_token":   testAccReplayToken,
+		"retries":     2,
+		"min_backoff": 0,
+		"max_backoff": 0,
+	}
+	for k, v := range extra {
+		raw[k] = v
+	}
+	p := Provider()
+	meta, diags := ProviderConfigure(context.Background(), schema.TestResourceDataRaw(t, p.Schema, raw), p)
+	if diags.HasError() {
+		t.Fatalf("ProviderConfigure: %v", diags)
+	}
+	return meta
+}
+
+// testFakeAPIConfig returns a provider block pointing at srv.
+func testFakeAPIConfig(srv *fakeapi.Server) string {
+	return fmt.Sprintf(`
+provider "wallarm" {
+  api_host    = %q
+  allow_http  = true
+  api_token   = %q
+  min_backoff = 0
+  max_backoff = 0
+}
+`, srv.URL, testAccReplayToken)
+}
+
+// testFakeAPITerraform skips the test unless a terraform binary is available
+// for resource.UnitTest.
+func testFakeAPITerraform(t *testing.T) {
+	t.Helper()
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") != "" {
+		return
+	}
+	if _, err := exec.LookPath("terraform"); err != nil {
+		t.Skip("terraform binary not found; set TF_ACC_TERRAFORM_PATH to run")
+	}
+}
+
+func TestFakeAPI_ProviderConfigureResolvesClientID(t *testing.T) {
+	meta := testFakeAPIMeta(t, testFakeAPIServer(t), nil)
+	if got := meta.(*ProviderMeta).DefaultClientID; got != fakeapi.DefaultClientID {
+		t.Errorf("DefaultClientID = %d, want %d", got, fakeapi.DefaultClientID)
+	}
+}
+
+func TestFakeAPI_RuleModeLifecycle(t *testing.T) {
+	for _, prefetch := range []bool{true, false} {
+		t.Run(fmt.Sprintf("hint_prefetch=%t", prefetch), func(t *testing.T) {
+			srv := testFakeAPIServer(t)
+			settings := map[string]any{"hint_prefetch": prefetch}
+			meta := testFakeAPIMeta(t, srv, settings)
+			ctx := context.Background()
+			res := resourceWallarmMode()
+
+			d := schema.TestResourceDataRaw(t, res.Schema, map[string]any{
+				"mode": "block",
+				"action": []any{map[string]any{
+					"type":  "iequal",
+					"value": "Fake.Example.COM",
+					"point": map[string]any{"header": "HOST"},
+				}},
+			})
+			d.MarkNewResource()
+			if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+				t.Fatalf("Create: %v", diags)
+			}
+			if d.Id() == "" || srv.HintCount() != 1 {
+				t.Fatalf("Create: id %q, %d hints stored", d.Id(), srv.HintCount())
+			}
+
+			// Import rebuilds the state from the ID alone.
+			imported := res.Data(nil)
+			imported.SetId(d.Id())
+			states, err := res.Importer.StateContext(ctx, imported, meta)
+			if err != nil {
+				t.Fatalf("Import: %v", err)
+			}
+			if diags := res.ReadContext(ctx, states[0], meta); diags.HasError() {
+				t.Fatalf("Read after import: %v", diags)
+			}
+			if got := states[0].Get("mode"); got != "block" {
+				t.Errorf("imported mode = %v, want block", got)
+			}
+
+			// A rule deleted outside Terraform drops out of state on the
+			// next run's refresh.
+			srv.DeleteHintOutOfBand(d.Get("rule_id").(int))
+			if diags := res.ReadContext(ctx, d, testFakeAPIMeta(t, srv, settings)); diags.HasError() {
+				t.Fatalf("Read after drift: %v", diags)
+			}
+			if d.Id() != "" {
+				t.Errorf("id after out-of-band delete = %q, want empty", d.Id())
+			}
+		})
+	}
+}
+
+func TestFakeAPI_RuleModeDeleteRetriesLocked(t *testing.T) {
+	srv := testFakeAPIServer(t)
+	meta := testFakeAPIMeta(t, srv, map[string]any{"hint_prefetch": false})
+	ctx := context.Background()
+	res := resourceWallarmMode()
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]any{"mode": "monitoring"})
+	d.MarkNewResource()
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("Create: %v", diags)
+	}
+
+	srv.Fail(http.MethodPost, "/v1/objects/hint/delete", http.StatusLocked, 1)
+	if diags := res.DeleteContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("Delete: %v", diags)
+	}
+	if n := srv.HintCount(); n != 0 {
+		t.Errorf("%d hints left after delete", n)
+	}
+}
+
+func TestFakeAPI_ResourceUnitTest(t *testing.T) {
+	testFakeAPITerraform(t)
+	srv := testFakeAPIServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeAPIConfig(srv) + `
+resource "wallarm_rule_mode" "test" {
+  mode = "block"
+  action {
+    type  = "iequal"
+    value = "unit.example.com"
+    point = {
+      header = "HOST"
+    }
+  }
+}
+`,
+				Check: resource.TestCheckResourceAttr("wallarm_rule_mode.test", "mode", "block"),
+			},
+			{
+				ResourceName:      "wallarm_rule_mode.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"action", "on_conflict",
+				},
+			},
+			{
+				PreConfig: func() {
+					for _, id := range srv.HintIDs() {
+						srv.DeleteHintOutOfBand(id)
+					}
+				},
+				RefreshState:       true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			if n := srv.HintCount(); n != 0 {
+				return fmt.Errorf("%d hints left after destroy", n)
+			}
+			return nil
+		},
+	})
+}
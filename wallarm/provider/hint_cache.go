@@ -1,10 +1,13 @@
 package wallarm
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"slices"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	wallarm "github.com/wallarm/wallarm-go"
@@ -19,29 +22,77 @@ type CacheStats struct {
 	Passthroughs  int64     `json:"passthroughs"`
 	Invalidations int64     `json:"invalidations"`
 	LastFetchAt   time.Time `json:"last_fetch_at"`
+	SnapshotHits  int64     `json:"snapshot_hits"`
+}
+
+// HintCounter returns the number of non-system hints of a client. Knowing the
+// total lets HintCache fetch the remaining pages in parallel. It is optional:
+// without it, or once it fails, pages are fetched one at a time.
+type HintCounter func(clientID int) (int, error)
+
+// rawHintCounter counts hints with POST /v1/objects/hint/count, which neither
+// wallarm-go nor the API reference covers; it mirrors the attack count
+// endpoint wallarm-go wraps. It takes the HintRead filter of the page fetches.
+func rawHintCounter(raw *rawAPI) HintCounter {
+	return func(clientID int) (int, error) {
+		systemFalse := false
+		var resp struct {
+			Body int `json:"body"`
+		}
+		err := raw.do(context.Background(), "POST", "/v1/objects/hint/count", map[string]any{
+			"filter": &wallarm.HintFilter{Clientid: []int{clientID}, System: &systemFalse},
+		}, &resp)
+		return resp.Body, err
+	}
 }
 
 // HintCache provides a thread-safe, lazily-paginated cache of hints keyed by hint ID.
 //
 // Instead of bulk-loading all hints upfront, it fetches pages on demand:
-// - First Read for ID 123 → fetch page 1 (200 hints) → cache all → check for 123
+// - First Read for ID 123 → fetch page 1 (500 hints) → cache all → check for 123
 // - Next Read for ID 456 → check cache → hit (was on page 1) → return
-// - Next Read for ID 999 → check cache → miss → fetch page 2 → check → found
+// - Next Read for ID 999 → check cache → miss → fetch more pages → check → found
 //
 // This minimizes API calls: if 5 managed rules are all on page 1, only 1 API call.
+// Page 1 is always fetched alone. When it is full and a HintCounter reports
+// the total, the remaining pages are fetched in waves of up to concurrency
+// parallel requests; otherwise one page at a time.
+//
+// Lookups never take a lock: hints is a sync.Map, and pages are stored in it
+// as they arrive, so reads of cached hints are not blocked by a fetch in
+// progress. fetchMu only serializes the fetching itself.
 type HintCache struct {
-	mu          sync.Mutex
-	hints       map[int]*wallarm.ActionBody
-	nextOffset  int  // next page offset to fetch
-	fullyLoaded bool // all pages have been fetched
-	clientID    int  // set on first fetch, used for consistency
+	hints       sync.Map // int → *wallarm.ActionBody
+	fullyLoaded atomic.Bool
+
+	fetchMu    sync.Mutex
+	nextOffset int // next page offset to fetch
+	total      int // hint count reported by counter, 0 if unknown
+
+	concurrency int
+	counter     HintCounter
+
+	// snapshot, when set, persists complete loads across runs. The first
+	// load of a run tries it before fetching (see hint_snapshot.go).
+	snapshot          *HintSnapshotStore
+	snapshotTried     bool
+	snapshotLoadedAt  time.Time // full load the cached hints descend from
+	snapshotFetchedAt time.Time // last full load or delta of the cached hints
+	snapshotClientID  int       // client of the cached hints
+	skipped           []int     // credential stuffing hint IDs of the last complete load
+	// base is the complete load Invalidate dropped; the next load revalidates
+	// it with a delta instead of fetching every page. deltaFailed stops
+	// revalidations after a delta query failed.
+	base        *hintSnapshot
+	deltaFailed bool
 
 	// stats
-	cacheHits     int64
-	pageFetches   int64
-	passthroughs  int64
-	invalidations int64
-	lastFetchAt   time.Time
+	cacheHits     atomic.Int64
+	pageFetches   atomic.Int64
+	passthroughs  atomic.Int64
+	invalidations atomic.Int64
+	lastFetchAt   atomic.Int64 // UnixNano, 0 before the first fetch
+	snapshotHits  atomic.Int64
 }
 
 // isCredentialStuffingType returns true for rule types that are served by the
@@ -52,155 +103,248 @@ func isCredentialStuffingType(t string) bool {
 	return t == "credentials_point" || t == "credentials_regex"
 }
 
-// NewHintCache creates an empty HintCache.
+// NewHintCache creates an empty HintCache that fetches one page at a time.
 func NewHintCache() *HintCache {
-	return &HintCache{
-		hints: make(map[int]*wallarm.ActionBody),
+	return &HintCache{concurrency: 1}
+}
+
+// SetConcurrency sets the HintCounter and the maximum number of pages fetched
+// in parallel. A nil counter or a concurrency below 2 keeps pages sequential.
+func (c *HintCache) SetConcurrency(counter HintCounter, concurrency int) {
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+	c.counter = counter
+	c.concurrency = max(concurrency, 1)
+}
+
+// SetSnapshot makes the cache persist complete loads to store and start from
+// the stored snapshot. Loads then always fetch every page.
+func (c *HintCache) SetSnapshot(store *HintSnapshotStore) {
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+	c.snapshot = store
+}
+
+func (c *HintCache) load(hintID int) (*wallarm.ActionBody, bool) {
+	v, ok := c.hints.Load(hintID)
+	if !ok {
+		return nil, false
 	}
+	return v.(*wallarm.ActionBody), true
+}
+
+// Len returns the number of cached hints.
+func (c *HintCache) Len() int {
+	n := 0
+	c.hints.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	return n
 }
 
 // GetOrFetch returns a cached hint by ID. If not cached, fetches pages lazily
 // until the hint is found or all pages are exhausted.
 func (c *HintCache) GetOrFetch(hintID, clientID int, api wallarm.API) (*wallarm.ActionBody, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	// Already cached?
-	if h, ok := c.hints[hintID]; ok {
-		c.cacheHits++
+	if h, ok := c.load(hintID); ok {
+		c.cacheHits.Add(1)
 		return h, nil
 	}
-
-	// Already fetched everything? Not found.
-	if c.fullyLoaded {
-		log.Printf("[DEBUG] HintCache: MISS hint_id=%d (fully loaded, %d hints cached — rule may be deleted)", hintID, len(c.hints))
+	if c.fullyLoaded.Load() {
+		log.Printf("[DEBUG] HintCache: MISS hint_id=%d (fully loaded, %d hints cached — rule may be deleted)", hintID, c.Len())
 		return nil, nil
 	}
 
-	// Set clientID on first fetch
-	if c.clientID == 0 {
-		c.clientID = clientID
-	}
-
-	// Paginate until found or exhausted
-	systemFalse := false
-	for {
-		log.Printf("[DEBUG] HintCache: fetching page offset=%d limit=%d for hint_id=%d", c.nextOffset, HintBulkFetchLimit, hintID)
-
-		resp, err := api.HintRead(&wallarm.HintRead{
-			Limit:     HintBulkFetchLimit,
-			Offset:    c.nextOffset,
-			OrderBy:   "id",
-			OrderDesc: true,
-			Filter: &wallarm.HintFilter{
-				Clientid: []int{clientID},
-				System:   &systemFalse,
-			},
-		})
-		if err != nil {
-			return nil, fmt.Errorf("HintCache: page fetch failed at offset %d: %w", c.nextOffset, err)
-		}
-
-		c.pageFetches++
-		c.lastFetchAt = time.Now()
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
 
-		if resp.Body == nil || len(*resp.Body) == 0 {
-			c.fullyLoaded = true
-			log.Printf("[DEBUG] HintCache: fully loaded — %d hints cached, hint_id=%d not found", len(c.hints), hintID)
-			return nil, nil
-		}
-
-		batch := *resp.Body
-		for i := range batch {
-			if isCredentialStuffingType(batch[i].Type) {
-				continue
-			}
-			c.hints[batch[i].ID] = &batch[i]
-		}
-
-		c.nextOffset += HintBulkFetchLimit
-
-		// Found it?
-		if h, ok := c.hints[hintID]; ok {
-			log.Printf("[DEBUG] HintCache: found hint_id=%d after fetching page (cache size: %d)", hintID, len(c.hints))
-			return h, nil
-		}
+	// Another reader may have fetched the page while we waited.
+	if h, ok := c.load(hintID); ok {
+		c.cacheHits.Add(1)
+		return h, nil
+	}
 
-		// Last page?
-		if len(batch) < HintBulkFetchLimit {
-			c.fullyLoaded = true
-			log.Printf("[DEBUG] HintCache: fully loaded — %d hints cached, hint_id=%d not found", len(c.hints), hintID)
-			return nil, nil
-		}
+	found := func() bool {
+		_, ok := c.hints.Load(hintID)
+		return ok
+	}
+	if err := c.fetch(clientID, api, found); err != nil {
+		return nil, err
+	}
+	if h, ok := c.load(hintID); ok {
+		log.Printf("[DEBUG] HintCache: found hint_id=%d after fetching pages (cache size: %d)", hintID, c.Len())
+		return h, nil
 	}
+	log.Printf("[DEBUG] HintCache: fully loaded — %d hints cached, hint_id=%d not found", c.Len(), hintID)
+	return nil, nil
 }
 
 // LoadAll fetches ALL hints into cache. Used by data.wallarm_rules which needs
 // the complete set. After this call, fullyLoaded is true.
 func (c *HintCache) LoadAll(clientID int, api wallarm.API) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.fullyLoaded {
+	if c.fullyLoaded.Load() {
 		return nil
 	}
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
 
 	// Continue from where we left off (may already have some pages cached)
-	systemFalse := false
-	for {
-		resp, err := api.HintRead(&wallarm.HintRead{
-			Limit:     HintBulkFetchLimit,
-			Offset:    c.nextOffset,
-			OrderBy:   "id",
-			OrderDesc: true,
-			Filter: &wallarm.HintFilter{
-				Clientid: []int{clientID},
-				System:   &systemFalse,
-			},
-		})
-		if err != nil {
-			return fmt.Errorf("HintCache: LoadAll failed at offset %d: %w", c.nextOffset, err)
-		}
+	if err := c.fetch(clientID, api, func() bool { return false }); err != nil {
+		return err
+	}
+	log.Printf("[INFO] HintCache: LoadAll complete — %d hints cached", c.Len())
+	return nil
+}
 
-		c.pageFetches++
-		c.lastFetchAt = time.Now()
+// fetch fetches pages until done reports true or the last page is reached.
+// With a snapshot store, a load from the first page revalidates the load
+// dropped by Invalidate or, on the first load of the run, restores the
+// snapshot; otherwise it fetches every page and saves a snapshot. The caller
+// holds fetchMu.
+func (c *HintCache) fetch(clientID int, api wallarm.API, done func() bool) error {
+	if c.snapshot == nil || c.nextOffset != 0 {
+		return c.fetchUntil(clientID, api, done)
+	}
+	if base := c.base; base != nil {
+		c.base = nil
+		if base.ClientID == clientID && c.revalidate(clientID, base) {
+			return nil
+		}
+	} else if !c.snapshotTried {
+		c.snapshotTried = true
+		if c.restoreSnapshot(clientID) {
+			c.snapshotHits.Add(1)
+			return nil
+		}
+	}
+	startedAt := time.Now()
+	c.skipped = nil
+	if err := c.fetchUntil(clientID, api, func() bool { return false }); err != nil {
+		return err
+	}
+	c.snapshotLoadedAt = startedAt
+	c.saveSnapshot(clientID, startedAt)
+	return nil
+}
 
-		if resp.Body == nil || len(*resp.Body) == 0 {
-			break
+func (c *HintCache) fetchUntil(clientID int, api wallarm.API, done func() bool) error {
+	for !c.fullyLoaded.Load() && !done() {
+		pages := 1
+		if c.total > 0 && c.concurrency > 1 {
+			// At least one page past the known total, to notice hints
+			// created since it was counted.
+			remaining := (c.total - c.nextOffset + HintBulkFetchLimit - 1) / HintBulkFetchLimit
+			pages = min(max(remaining, 1), c.concurrency)
 		}
+		log.Printf("[DEBUG] HintCache: fetching %d page(s) from offset=%d limit=%d", pages, c.nextOffset, HintBulkFetchLimit)
 
-		batch := *resp.Body
-		for i := range batch {
-			if isCredentialStuffingType(batch[i].Type) {
-				continue
+		sizes, skipped, err := c.fetchPages(clientID, api, c.nextOffset, pages)
+		if err != nil {
+			return err
+		}
+		c.skipped = append(c.skipped, skipped...)
+		first := c.nextOffset == 0
+		c.nextOffset += pages * HintBulkFetchLimit
+		for _, n := range sizes {
+			if n < HintBulkFetchLimit {
+				c.fullyLoaded.Store(true)
+			}
+		}
+		if first && !c.fullyLoaded.Load() && c.concurrency > 1 {
+			if total, ok := c.count(clientID); ok {
+				c.total = total
+				log.Printf("[DEBUG] HintCache: %d hints, fetching up to %d pages in parallel", total, c.concurrency)
 			}
-			c.hints[batch[i].ID] = &batch[i]
 		}
+	}
+	return nil
+}
 
-		c.nextOffset += HintBulkFetchLimit
+// count asks the counter for the hint total. The first failure drops the
+// counter, so the rest of the run pages sequentially without retrying an
+// endpoint the API may not serve. The caller holds fetchMu.
+func (c *HintCache) count(clientID int) (int, bool) {
+	if c.counter == nil {
+		return 0, false
+	}
+	total, err := c.counter(clientID)
+	if err != nil {
+		log.Printf("[WARN] HintCache: could not count hints, fetching pages sequentially for the rest of the run: %s", err)
+		c.counter = nil
+		return 0, false
+	}
+	return total, true
+}
 
-		if len(batch) < HintBulkFetchLimit {
-			break
+// fetchPages fetches n consecutive pages starting at offset in parallel and
+// stores their hints. It returns the size of each page and the IDs of the
+// credential stuffing hints it skipped.
+func (c *HintCache) fetchPages(clientID int, api wallarm.API, offset, n int) ([]int, []int, error) {
+	sizes := make([]int, n)
+	skipped := make([][]int, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sizes[i], skipped[i], errs[i] = c.fetchPage(clientID, api, offset+i*HintBulkFetchLimit)
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, nil, fmt.Errorf("HintCache: page fetch failed at offset %d: %w", offset+i*HintBulkFetchLimit, err)
 		}
 	}
+	return sizes, slices.Concat(skipped...), nil
+}
 
-	c.fullyLoaded = true
-	log.Printf("[INFO] HintCache: LoadAll complete — %d hints cached", len(c.hints))
-	return nil
+func (c *HintCache) fetchPage(clientID int, api wallarm.API, offset int) (int, []int, error) {
+	systemFalse := false
+	resp, err := api.HintRead(&wallarm.HintRead{
+		Limit:     HintBulkFetchLimit,
+		Offset:    offset,
+		OrderBy:   "id",
+		OrderDesc: true,
+		Filter: &wallarm.HintFilter{
+			Clientid: []int{clientID},
+			System:   &systemFalse,
+		},
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	c.pageFetches.Add(1)
+	c.lastFetchAt.Store(time.Now().UnixNano())
+
+	if resp.Body == nil {
+		return 0, nil, nil
+	}
+	batch := *resp.Body
+	var skipped []int
+	for i := range batch {
+		if isCredentialStuffingType(batch[i].Type) {
+			skipped = append(skipped, batch[i].ID)
+			continue
+		}
+		c.hints.Store(batch[i].ID, &batch[i])
+	}
+	return len(batch), skipped, nil
 }
 
 // All returns all cached hints sorted by ID descending.
 // Returns nil if not fully loaded.
 func (c *HintCache) All() []wallarm.ActionBody {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	if !c.fullyLoaded {
+	if !c.fullyLoaded.Load() {
 		return nil
 	}
-	result := make([]wallarm.ActionBody, 0, len(c.hints))
-	for _, h := range c.hints {
-		result = append(result, *h)
-	}
+	var result []wallarm.ActionBody
+	c.hints.Range(func(_, v any) bool {
+		result = append(result, *v.(*wallarm.ActionBody))
+		return true
+	})
 	sort.Slice(result, func(i, j int) bool {
 		return result[i].ID > result[j].ID
 	})
@@ -213,46 +357,51 @@ func (c *HintCache) Insert(hint *wallarm.ActionBody) {
 	if isCredentialStuffingType(hint.Type) {
 		return
 	}
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	if c.hints == nil {
-		c.hints = make(map[int]*wallarm.ActionBody)
-	}
-	c.hints[hint.ID] = hint
-	log.Printf("[DEBUG] HintCache: INSERT hint_id=%d (cache size: %d)", hint.ID, len(c.hints))
+	c.hints.Store(hint.ID, hint)
+	log.Printf("[DEBUG] HintCache: INSERT hint_id=%d", hint.ID)
 }
 
-// Invalidate clears the cache and resets pagination state.
-func (c *HintCache) Invalidate(caller string) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	prevCount := len(c.hints)
-	c.hints = make(map[int]*wallarm.ActionBody)
-	c.fullyLoaded = false
+// Invalidate clears the cache and resets pagination state. It waits for a
+// fetch in progress, which would otherwise store pages read before the
+// mutation. With a snapshot store, a complete load minus the deleted hint IDs
+// is kept as the base the next load revalidates.
+func (c *HintCache) Invalidate(caller string, deleted ...int) {
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+	prevCount := c.Len()
+	c.base = nil
+	if c.snapshot != nil && c.fullyLoaded.Load() && !c.deltaFailed {
+		c.base = c.snapshotOf(c.snapshotClientID, c.snapshotFetchedAt, deleted)
+	}
+	c.hints.Clear()
+	c.fullyLoaded.Store(false)
 	c.nextOffset = 0
-	c.invalidations++
-	log.Printf("[INFO] HintCache: INVALIDATED by %s — cleared %d cached hints (invalidation #%d)", caller, prevCount, c.invalidations)
+	c.total = 0
+	c.skipped = nil
+	n := c.invalidations.Add(1)
+	log.Printf("[INFO] HintCache: INVALIDATED by %s — cleared %d cached hints (invalidation #%d)", caller, prevCount, n)
 }
 
 // trackPassthrough increments the passthrough counter for non-cacheable queries.
 func (c *HintCache) trackPassthrough() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.passthroughs++
+	c.passthroughs.Add(1)
 }
 
 // Stats returns a snapshot of the cache's operational statistics.
 func (c *HintCache) Stats() CacheStats {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	var lastFetchAt time.Time
+	if ns := c.lastFetchAt.Load(); ns != 0 {
+		lastFetchAt = time.Unix(0, ns)
+	}
 	return CacheStats{
-		FullyLoaded:   c.fullyLoaded,
-		HintCount:     len(c.hints),
-		CacheHits:     c.cacheHits,
-		PageFetches:   c.pageFetches,
-		Passthroughs:  c.passthroughs,
-		Invalidations: c.invalidations,
-		LastFetchAt:   c.lastFetchAt,
+		FullyLoaded:   c.fullyLoaded.Load(),
+		HintCount:     c.Len(),
+		CacheHits:     c.cacheHits.Load(),
+		PageFetches:   c.pageFetches.Load(),
+		Passthroughs:  c.passthroughs.Load(),
+		Invalidations: c.invalidations.Load(),
+		LastFetchAt:   lastFetchAt,
+		SnapshotHits:  c.snapshotHits.Load(),
 	}
 }
 
@@ -270,19 +419,35 @@ func (c *HintCache) Stats() CacheStats {
 // Mutating methods:
 //   - HintCreate, HintUpdateV3: Insert into cache (no invalidation)
 //   - HintDelete: Delegates to underlying API (no caching)
+//
+// It also holds the ActionIndex used for action lookups by conditions;
+// HintCreate adds the created action to it.
 type CachedClient struct {
 	wallarm.API
-	hintCache *HintCache
+	hintCache   *HintCache
+	actionIndex *ActionIndex
 }
 
 // NewCachedClient wraps an existing wallarm.API with hint caching.
 func NewCachedClient(api wallarm.API) *CachedClient {
 	return &CachedClient{
-		API:       api,
-		hintCache: NewHintCache(),
+		API:         api,
+		hintCache:   NewHintCache(),
+		actionIndex: NewActionIndex(),
 	}
 }
 
+// SetPrefetchConcurrency lets the hint cache fetch up to concurrency pages in
+// parallel once counter has reported the total. See HintCache.
+func (c *CachedClient) SetPrefetchConcurrency(counter HintCounter, concurrency int) {
+	c.hintCache.SetConcurrency(counter, concurrency)
+}
+
+// SetHintSnapshot persists the hint cache across runs. See HintSnapshotStore.
+func (c *CachedClient) SetHintSnapshot(store *HintSnapshotStore) {
+	c.hintCache.SetSnapshot(store)
+}
+
 // TODO: add test — mock API, verify returns all non-credential-stuffing hints
 // AllRules loads all hints into cache and returns them.
 // Used by data.wallarm_rules which needs the complete set.
@@ -306,8 +471,8 @@ func (c *CachedClient) LogHintCacheStats() {
 	if s.CacheHits+s.PageFetches > 0 {
 		hitRate = float64(s.CacheHits) / float64(s.CacheHits+s.PageFetches) * 100
 	}
-	log.Printf("[INFO] HintCache stats: %d total | %d hits (%.1f%%) | %d page fetches | %d passthroughs | %d invalidations | %d hints cached",
-		total, s.CacheHits, hitRate, s.PageFetches, s.Passthroughs, s.Invalidations, s.HintCount)
+	log.Printf("[INFO] HintCache stats: %d total | %d hits (%.1f%%) | %d page fetches | %d passthroughs | %d invalidations | %d snapshot hits | %d hints cached",
+		total, s.CacheHits, hitRate, s.PageFetches, s.Passthroughs, s.Invalidations, s.SnapshotHits, s.HintCount)
 }
 
 // HintRead overrides the embedded API's HintRead. Flushes pending deletes first.
@@ -349,7 +514,8 @@ func (c *CachedClient) HintRead(body *wallarm.HintRead) (*wallarm.HintReadResp,
 	return c.API.HintRead(body)
 }
 
-// HintCreate delegates to the underlying API and inserts the new hint into cache.
+// HintCreate delegates to the underlying API and inserts the new hint into
+// cache and its action into the action index.
 func (c *CachedClient) HintCreate(body *wallarm.ActionCreate) (*wallarm.ActionCreateResp, error) {
 	resp, err := c.API.HintCreate(body)
 	if err != nil {
@@ -357,6 +523,15 @@ func (c *CachedClient) HintCreate(body *wallarm.ActionCreate) (*wallarm.ActionCr
 	}
 	if resp != nil && resp.Body != nil {
 		c.hintCache.Insert(resp.Body)
+		conditions := resp.Body.Action
+		if body.Action != nil {
+			conditions = *body.Action
+		}
+		c.actionIndex.Add(resp.Body.Clientid, resp.Body.Type, &wallarm.ActionEntry{
+			ID:         resp.Body.ActionID,
+			Clientid:   resp.Body.Clientid,
+			Conditions: conditions,
+		})
 	}
 	return resp, nil
 }
@@ -371,7 +546,11 @@ func (c *CachedClient) HintDelete(body *wallarm.HintDelete) (*wallarm.HintDelete
 	if err != nil {
 		return resp, err
 	}
-	c.hintCache.Invalidate("HintDelete")
+	var deleted []int
+	if body != nil && body.Filter != nil {
+		deleted = body.Filter.ID
+	}
+	c.hintCache.Invalidate("HintDelete", deleted...)
 	return resp, nil
 }
 
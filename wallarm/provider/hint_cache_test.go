@@ -2,10 +2,13 @@ package wallarm
 
 import (
 	"fmt"
+	"net/http"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/wallarm/terraform-provider-wallarm/wallarm/common/resourcerule"
 	wallarm "github.com/wallarm/wallarm-go"
 )
 
@@ -443,13 +446,13 @@ func TestHintCache_InsertSkipsCredentialStuffingTypes(t *testing.T) {
 	for _, tc := range cases {
 		id++
 		cache.Insert(&wallarm.ActionBody{ID: id, Type: tc.hintType})
-		_, held := cache.hints[id]
+		_, held := cache.load(id)
 		if held != tc.shouldHold {
 			t.Errorf("Insert(type=%q): held=%v, want %v", tc.hintType, held, tc.shouldHold)
 		}
 	}
 
-	if got, want := len(cache.hints), 3; got != want {
+	if got, want := cache.Len(), 3; got != want {
 		t.Errorf("cache size = %d, want %d", got, want)
 	}
 }
@@ -467,14 +470,14 @@ func TestHintCache_LoadAll(t *testing.T) {
 	if err := cache.LoadAll(1, mock); err != nil {
 		t.Fatalf("LoadAll: %v", err)
 	}
-	if !cache.fullyLoaded {
+	if !cache.fullyLoaded.Load() {
 		t.Errorf("fullyLoaded should be true after LoadAll")
 	}
-	if got := len(cache.hints); got != 50 {
+	if got := cache.Len(); got != 50 {
 		t.Errorf("cache size = %d, want 50 (credential_stuffing types filtered)", got)
 	}
 	for _, t2 := range []int{9001, 9002} {
-		if _, held := cache.hints[t2]; held {
+		if _, held := cache.load(t2); held {
 			t.Errorf("credential_stuffing hint %d should be filtered out of cache", t2)
 		}
 	}
@@ -673,3 +676,251 @@ func TestCachedClient_HintDelete_APIError(t *testing.T) {
 		t.Errorf("expected zero new HintRead API calls (cache preserved), got %d new", got-cacheCallsBeforeDelete)
 	}
 }
+
+// TestHintCache_ParallelPages verifies that once the counter reports the
+// total, the pages after the first are fetched in parallel, capped at the
+// configured concurrency.
+func TestHintCache_ParallelPages(t *testing.T) {
+	mock := &inFlightHintAPI{mockHintAPI: mockHintAPI{hints: makeHints(4*HintBulkFetchLimit + 100)}}
+	cache := NewHintCache()
+	cache.SetConcurrency(func(int) (int, error) { return len(mock.hints), nil }, 3)
+
+	if err := cache.LoadAll(1, mock); err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	if got, want := cache.Len(), len(mock.hints); got != want {
+		t.Errorf("cache size = %d, want %d", got, want)
+	}
+	// Page 1 alone, then pages 2-4 and page 5 in two waves.
+	if got := mock.callCount.Load(); got != 5 {
+		t.Errorf("HintRead calls = %d, want 5", got)
+	}
+	if got := mock.maxInFlight.Load(); got != 3 {
+		t.Errorf("max parallel page fetches = %d, want 3", got)
+	}
+}
+
+// TestHintCache_CounterErrorFetchesSequentially verifies the fallback to one
+// page at a time when the hint count is unavailable, and that the counter is
+// not retried for the rest of the run.
+func TestHintCache_CounterErrorFetchesSequentially(t *testing.T) {
+	mock := &inFlightHintAPI{mockHintAPI: mockHintAPI{hints: makeHints(2*HintBulkFetchLimit + 1)}}
+	cache := NewHintCache()
+	var counts atomic.Int32
+	cache.SetConcurrency(func(int) (int, error) {
+		counts.Add(1)
+		return 0, fmt.Errorf("not found")
+	}, 4)
+
+	if err := cache.LoadAll(1, mock); err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	if got := cache.Len(); got != len(mock.hints) {
+		t.Errorf("cache size = %d, want %d", got, len(mock.hints))
+	}
+	if got := mock.maxInFlight.Load(); got != 1 {
+		t.Errorf("max parallel page fetches = %d, want 1", got)
+	}
+
+	cache.Invalidate("test")
+	if err := cache.LoadAll(1, mock); err != nil {
+		t.Fatalf("LoadAll after Invalidate: %v", err)
+	}
+	if got := counts.Load(); got != 1 {
+		t.Errorf("counter calls = %d, want 1", got)
+	}
+}
+
+// TestHintCache_LookupDuringFetch verifies that reads of cached hints are
+// served while other pages are still being fetched.
+func TestHintCache_LookupDuringFetch(t *testing.T) {
+	release := make(chan struct{})
+	mock := &inFlightHintAPI{
+		mockHintAPI: mockHintAPI{hints: makeHints(3 * HintBulkFetchLimit)},
+		gate:        release,
+	}
+	cache := NewHintCache()
+	cache.SetConcurrency(func(int) (int, error) { return len(mock.hints), nil }, 2)
+
+	loaded := make(chan error)
+	go func() { loaded <- cache.LoadAll(1, mock) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for cache.Len() < HintBulkFetchLimit {
+		if time.Now().After(deadline) {
+			t.Fatal("page 1 was not cached")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	got := make(chan *wallarm.ActionBody)
+	go func() {
+		h, _ := cache.GetOrFetch(1000, 1, mock)
+		got <- h
+	}()
+	select {
+	case h := <-got:
+		if h == nil || h.ID != 1000 {
+			t.Errorf("GetOrFetch(1000) = %+v", h)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("lookup of a cached hint blocked on the page fetch in progress")
+	}
+
+	close(release)
+	if err := <-loaded; err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	if got := cache.Len(); got != len(mock.hints) {
+		t.Errorf("cache size = %d, want %d", got, len(mock.hints))
+	}
+}
+
+// inFlightHintAPI tracks the peak number of concurrent HintRead calls. When
+// gate is set, pages after the first wait for it to close.
+type inFlightHintAPI struct {
+	mockHintAPI
+	gate        chan struct{}
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func (m *inFlightHintAPI) HintRead(body *wallarm.HintRead) (*wallarm.HintReadResp, error) {
+	n := m.inFlight.Add(1)
+	defer m.inFlight.Add(-1)
+	for {
+		peak := m.maxInFlight.Load()
+		if n <= peak || m.maxInFlight.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	if m.gate != nil && body.Offset > 0 {
+		<-m.gate
+	} else {
+		// Let parallel fetches overlap.
+		time.Sleep(10 * time.Millisecond)
+	}
+	return m.mockHintAPI.HintRead(body)
+}
+
+// TestCachedClient_ActionIndex verifies that conditions lookups paginate
+// ActionList once per hint type and see actions created by HintCreate.
+func TestCachedClient_ActionIndex(t *testing.T) {
+	existing := makeAction("existing.example.com")
+	created := makeAction("created.example.com")
+	mock := &mockHintAPI{
+		actions: append(fillerActions(10, 100), wallarm.ActionEntry{ID: 42, Clientid: 1, Conditions: existing}),
+		createResp: &wallarm.ActionCreateResp{Status: 200, Body: &wallarm.ActionBody{
+			ID: 7, ActionID: 43, Clientid: 1, Type: "wallarm_mode",
+		}},
+	}
+	cached := NewCachedClient(mock)
+
+	for range 3 {
+		got, err := findActionByConditionsHash(cached, 1, "wallarm_mode", resourcerule.ConditionsHash(existing), len(existing))
+		if err != nil {
+			t.Fatalf("findActionByConditionsHash: %v", err)
+		}
+		if got == nil || got.ID != 42 {
+			t.Fatalf("expected action 42, got %+v", got)
+		}
+	}
+	if got := mock.actionCallCount.Load(); got != 1 {
+		t.Errorf("ActionList calls = %d, want 1", got)
+	}
+
+	if _, err := cached.HintCreate(&wallarm.ActionCreate{Type: "wallarm_mode", Clientid: 1, Action: &created}); err != nil {
+		t.Fatalf("HintCreate: %v", err)
+	}
+	got, err := findActionByConditionsHash(cached, 1, "wallarm_mode", resourcerule.ConditionsHash(created), len(created))
+	if err != nil {
+		t.Fatalf("findActionByConditionsHash: %v", err)
+	}
+	if got == nil || got.ID != 43 {
+		t.Fatalf("expected created action 43, got %+v", got)
+	}
+	if got := mock.actionCallCount.Load(); got != 1 {
+		t.Errorf("ActionList calls after HintCreate = %d, want 1", got)
+	}
+}
+
+// gatedActionAPI blocks ActionList calls for gatedType until gate closes.
+type gatedActionAPI struct {
+	mockHintAPI
+	gatedType string
+	gate      chan struct{}
+	started   chan struct{}
+}
+
+func (m *gatedActionAPI) ActionList(params *wallarm.ActionListParams) (*wallarm.ActionListResponse, error) {
+	if len(params.Filter.HintType) > 0 && params.Filter.HintType[0] == m.gatedType {
+		close(m.started)
+		<-m.gate
+	}
+	return m.mockHintAPI.ActionList(params)
+}
+
+// TestActionIndex_ScopesLoadIndependently verifies that a scope still loading
+// does not block lookups in other scopes.
+func TestActionIndex_ScopesLoadIndependently(t *testing.T) {
+	conditions := makeAction("scoped.example.com")
+	mock := &gatedActionAPI{
+		mockHintAPI: mockHintAPI{actions: []wallarm.ActionEntry{{ID: 42, Clientid: 1, Conditions: conditions}}},
+		gatedType:   "wallarm_mode",
+		gate:        make(chan struct{}),
+		started:     make(chan struct{}),
+	}
+	index := NewActionIndex()
+	hash := resourcerule.ConditionsHash(conditions)
+
+	slow := make(chan error, 1)
+	go func() {
+		_, err := index.Find(mock, actionIndexScope{clientID: 1, hintType: "wallarm_mode"}, hash)
+		slow <- err
+	}()
+	<-mock.started
+
+	done := make(chan *wallarm.ActionEntry, 1)
+	go func() {
+		got, err := index.Find(mock, actionIndexScope{clientID: 1, hintType: "disable_stamp"}, hash)
+		if err != nil {
+			t.Errorf("Find: %v", err)
+		}
+		done <- got
+	}()
+	select {
+	case got := <-done:
+		if got == nil || got.ID != 42 {
+			t.Errorf("expected action 42, got %+v", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("lookup in another scope blocked on a loading scope")
+	}
+
+	close(mock.gate)
+	if err := <-slow; err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if got := index.Len(); got != 2 {
+		t.Errorf("index size = %d, want 2", got)
+	}
+}
+
+func TestRawHintCounter(t *testing.T) {
+	raw, calls := testIntegrationCheckServer(t, http.StatusOK, `{"status": 200, "body": 1234}`)
+	n, err := rawHintCounter(raw)(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1234 {
+		t.Errorf("count = %d, want 1234", n)
+	}
+	if len(*calls) != 1 || (*calls)[0] != "POST /v1/objects/hint/count token" {
+		t.Errorf("calls: got %v", *calls)
+	}
+
+	raw, _ = testIntegrationCheckServer(t, http.StatusNotFound, `{"status": 404}`)
+	if _, err := rawHintCounter(raw)(1); err == nil {
+		t.Error("expected an error for HTTP 404")
+	}
+}
@@ -0,0 +1,262 @@
+package wallarm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	wallarm "github.com/wallarm/wallarm-go"
+)
+
+// hintSnapshotVersion is bumped when the snapshot file format changes; files
+// of another version are ignored.
+const hintSnapshotVersion = 1
+
+// hintSnapshotDeltaSlack widens the delta query window to cover clock skew
+// between this host and the API. Hints fetched twice are simply overwritten.
+const hintSnapshotDeltaSlack = 5 * time.Minute
+
+// HintDelta returns the hints of a client updated since the given time,
+// including ones created since.
+type HintDelta func(clientID int, since time.Time) ([]wallarm.ActionBody, error)
+
+// HintSnapshotStore persists complete hint cache loads in a directory, one
+// file per API host and client, so the next run does not download the whole
+// ruleset again.
+//
+// A snapshot is restored only if it is younger than ttl (counted from the full
+// load it descends from) and, after applying the delta of hints updated since
+// it was written, the number of hints matches the API's hint count. A delta
+// cannot see deletions, so the count check is what catches them; on any
+// mismatch or error the cache falls back to a full load.
+type HintSnapshotStore struct {
+	dir   string
+	ttl   time.Duration
+	host  string
+	delta HintDelta
+}
+
+// NewHintSnapshotStore creates a store in dir for snapshots of apiHost.
+func NewHintSnapshotStore(dir string, ttl time.Duration, apiHost string, delta HintDelta) *HintSnapshotStore {
+	return &HintSnapshotStore{dir: dir, ttl: ttl, host: apiHost, delta: delta}
+}
+
+type hintSnapshot struct {
+	Version  int `json:"version"`
+	ClientID int `json:"client_id"`
+	// LoadedAt is the full load the snapshot descends from; the TTL counts
+	// from it. FetchedAt is the last full load or delta.
+	LoadedAt  time.Time            `json:"loaded_at"`
+	FetchedAt time.Time            `json:"fetched_at"`
+	Hints     []wallarm.ActionBody `json:"hints"`
+	// SkippedIDs are credential stuffing hints: not cached, but part of the
+	// API's hint count.
+	SkippedIDs []int `json:"skipped_ids,omitempty"`
+}
+
+func (s *HintSnapshotStore) path(clientID int) string {
+	sum := sha256.Sum256([]byte(s.host))
+	return filepath.Join(s.dir, fmt.Sprintf("hints_%d_%s.json", clientID, hex.EncodeToString(sum[:4])))
+}
+
+func (s *HintSnapshotStore) read(clientID int) (*hintSnapshot, error) {
+	b, err := os.ReadFile(s.path(clientID))
+	if err != nil {
+		return nil, err
+	}
+	var snap hintSnapshot
+	if err := json.Unmarshal(b, &snap); err != nil {
+		return nil, fmt.Errorf("corrupt snapshot: %w", err)
+	}
+	switch {
+	case snap.Version != hintSnapshotVersion:
+		return nil, fmt.Errorf("snapshot version %d, want %d", snap.Version, hintSnapshotVersion)
+	case snap.ClientID != clientID:
+		return nil, fmt.Errorf("snapshot is for client %d", snap.ClientID)
+	case time.Since(snap.LoadedAt) > s.ttl:
+		return nil, fmt.Errorf("snapshot from %s is older than the %s TTL", snap.LoadedAt.Format(time.RFC3339), s.ttl)
+	}
+	return &snap, nil
+}
+
+// write replaces the snapshot file atomically; concurrent runs see either
+// the old or the new snapshot.
+func (s *HintSnapshotStore) write(snap *hintSnapshot) error {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return err
+	}
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, ".hints-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(snap.ClientID))
+}
+
+// restoreSnapshot fills the cache from the client's snapshot revalidated with
+// a delta query. It returns false, leaving the cache empty, when the snapshot
+// is missing, expired or cannot be trusted. The caller holds fetchMu.
+func (c *HintCache) restoreSnapshot(clientID int) bool {
+	snap, err := c.snapshot.read(clientID)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[INFO] HintCache: not using snapshot for client %d: %s", clientID, err)
+		}
+		return false
+	}
+	return c.revalidate(clientID, snap)
+}
+
+// revalidate fills the cache from base plus the hints updated since it was
+// fetched, and saves the result as the new snapshot. It returns false, leaving
+// the cache empty, when base is expired, the delta query fails or the merged
+// hints do not add up to the API's hint count. The caller holds fetchMu.
+func (c *HintCache) revalidate(clientID int, base *hintSnapshot) bool {
+	if c.counter == nil {
+		log.Printf("[INFO] HintCache: not revalidating cached hints for client %d: hint count unavailable to verify them", clientID)
+		return false
+	}
+	if time.Since(base.LoadedAt) > c.snapshot.ttl {
+		log.Printf("[INFO] HintCache: cached hints for client %d are older than the %s TTL, doing a full load", clientID, c.snapshot.ttl)
+		return false
+	}
+
+	startedAt := time.Now()
+	delta, err := c.snapshot.delta(clientID, base.FetchedAt.Add(-hintSnapshotDeltaSlack))
+	if err != nil {
+		log.Printf("[WARN] HintCache: hint delta query for client %d failed, doing full loads for the rest of the run: %s", clientID, err)
+		c.deltaFailed = true
+		return false
+	}
+	hints := make(map[int]wallarm.ActionBody, len(base.Hints)+len(delta))
+	for _, h := range base.Hints {
+		hints[h.ID] = h
+	}
+	skipped := make(map[int]bool, len(base.SkippedIDs))
+	for _, id := range base.SkippedIDs {
+		skipped[id] = true
+	}
+	for _, h := range delta {
+		if isCredentialStuffingType(h.Type) {
+			skipped[h.ID] = true
+		} else {
+			hints[h.ID] = h
+		}
+	}
+
+	total, ok := c.count(clientID)
+	if !ok || total != len(hints)+len(skipped) {
+		log.Printf("[INFO] HintCache: cached hints for client %d do not match the API (%d hints after delta, count %d), doing a full load",
+			clientID, len(hints)+len(skipped), total)
+		return false
+	}
+
+	for id, h := range hints {
+		c.hints.Store(id, &h)
+	}
+	c.skipped = c.skipped[:0]
+	for id := range skipped {
+		c.skipped = append(c.skipped, id)
+	}
+	c.fullyLoaded.Store(true)
+	c.snapshotLoadedAt = base.LoadedAt
+	log.Printf("[INFO] HintCache: revalidated %d hints for client %d (%d updated since %s)",
+		len(hints), clientID, len(delta), base.FetchedAt.Format(time.RFC3339))
+	c.saveSnapshot(clientID, startedAt)
+	return true
+}
+
+// snapshotOf returns the cached hints as a snapshot, without the deleted
+// hint IDs. The caller holds fetchMu.
+func (c *HintCache) snapshotOf(clientID int, fetchedAt time.Time, deleted []int) *hintSnapshot {
+	snap := &hintSnapshot{
+		Version:   hintSnapshotVersion,
+		ClientID:  clientID,
+		LoadedAt:  c.snapshotLoadedAt,
+		FetchedAt: fetchedAt,
+	}
+	c.hints.Range(func(_, v any) bool {
+		if h := v.(*wallarm.ActionBody); !slices.Contains(deleted, h.ID) {
+			snap.Hints = append(snap.Hints, *h)
+		}
+		return true
+	})
+	for _, id := range c.skipped {
+		if !slices.Contains(deleted, id) {
+			snap.SkippedIDs = append(snap.SkippedIDs, id)
+		}
+	}
+	return snap
+}
+
+// saveSnapshot writes the fully loaded cache. Failures only cost the next
+// run a full load, so they are logged. The caller holds fetchMu.
+func (c *HintCache) saveSnapshot(clientID int, fetchedAt time.Time) {
+	c.snapshotClientID = clientID
+	c.snapshotFetchedAt = fetchedAt
+	if err := c.snapshot.write(c.snapshotOf(clientID, fetchedAt, nil)); err != nil {
+		log.Printf("[WARN] HintCache: could not write snapshot for client %d: %s", clientID, err)
+	}
+}
+
+// rawHintDelta queries hints by update time, a filter wallarm-go's HintFilter
+// does not have and the API reference does not list. When the API ignores it,
+// the query fails and the cache falls back to a full load. Pages are fetched until a short page, up to the same page cap
+// as findActionByConditionsHash.
+func rawHintDelta(raw *rawAPI) HintDelta {
+	return func(clientID int, since time.Time) ([]wallarm.ActionBody, error) {
+		systemFalse := false
+		var hints []wallarm.ActionBody
+		for page := 0; page < findActionByConditionsHashPageCap; page++ {
+			offset := page * HintBulkFetchLimit
+			var resp wallarm.HintReadResp
+			err := raw.do(context.Background(), "POST", "/v1/objects/hint", map[string]any{
+				"filter": map[string]any{
+					"clientid":   []int{clientID},
+					"system":     &systemFalse,
+					"updated_at": [][]int64{{since.Unix(), time.Now().Add(hintSnapshotDeltaSlack).Unix()}},
+				},
+				"order_by":   "id",
+				"order_desc": true,
+				"limit":      HintBulkFetchLimit,
+				"offset":     offset,
+			}, &resp)
+			if err != nil {
+				return nil, err
+			}
+			if resp.Body == nil {
+				return hints, nil
+			}
+			// The API reference does not list updated_at among the hint
+			// filters. A hint older than the window means it was ignored.
+			for _, h := range *resp.Body {
+				if h.UpdatedAt != 0 && int64(h.UpdatedAt) < since.Unix() {
+					return nil, fmt.Errorf("hint delta: updated_at filter not applied (hint %d updated at %d)", h.ID, h.UpdatedAt)
+				}
+			}
+			hints = append(hints, *resp.Body...)
+			if len(*resp.Body) < HintBulkFetchLimit {
+				return hints, nil
+			}
+		}
+		return nil, fmt.Errorf("hint delta: pagination cap (%d pages × %d) exceeded", findActionByConditionsHashPageCap, HintBulkFetchLimit)
+	}
+}
@@ -0,0 +1,196 @@
+package wallarm
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	wallarm "github.com/wallarm/wallarm-go"
+)
+
+// snapshotRun is one provider run against a snapshot directory: a fresh
+// cache over a fresh mock, as after a process restart.
+type snapshotRun struct {
+	cache  *HintCache
+	mock   *mockHintAPI
+	deltas int
+}
+
+func newSnapshotRun(t *testing.T, dir string, ttl time.Duration, hints []wallarm.ActionBody, delta []wallarm.ActionBody) *snapshotRun {
+	t.Helper()
+	r := &snapshotRun{mock: &mockHintAPI{hints: hints}, cache: NewHintCache()}
+	r.cache.SetConcurrency(func(int) (int, error) { return len(r.mock.hints), nil }, 1)
+	r.cache.SetSnapshot(NewHintSnapshotStore(dir, ttl, "https://api.wallarm.com", func(int, time.Time) ([]wallarm.ActionBody, error) {
+		r.deltas++
+		return delta, nil
+	}))
+	return r
+}
+
+func TestHintSnapshot_RestoredWithDelta(t *testing.T) {
+	dir := t.TempDir()
+	hints := makeHints(HintBulkFetchLimit + 10)
+	hints = append(hints, wallarm.ActionBody{ID: 9001, Type: "credentials_regex"})
+
+	first := newSnapshotRun(t, dir, time.Hour, hints, nil)
+	// A lookup on page 1 still loads every page so the snapshot is complete.
+	if h, err := first.cache.GetOrFetch(1000, 1, first.mock); err != nil || h == nil {
+		t.Fatalf("GetOrFetch: %v, %v", h, err)
+	}
+	if got := first.mock.callCount.Load(); got != 2 {
+		t.Errorf("first run HintRead calls = %d, want 2", got)
+	}
+	if first.deltas != 0 || first.cache.Stats().SnapshotHits != 0 {
+		t.Errorf("first run used a snapshot: deltas=%d stats=%+v", first.deltas, first.cache.Stats())
+	}
+
+	// Second run: one hint changed, one created since the snapshot.
+	updated := hints[3]
+	updated.Mode = "block"
+	created := wallarm.ActionBody{ID: 5000, Type: "wallarm_mode"}
+	second := newSnapshotRun(t, dir, time.Hour, append(hints, created), []wallarm.ActionBody{updated, created})
+
+	h, err := second.cache.GetOrFetch(updated.ID, 1, second.mock)
+	if err != nil {
+		t.Fatalf("GetOrFetch: %v", err)
+	}
+	if h == nil || h.Mode != "block" {
+		t.Errorf("expected the delta version of hint %d, got %+v", updated.ID, h)
+	}
+	if h, _ := second.cache.GetOrFetch(created.ID, 1, second.mock); h == nil {
+		t.Error("hint created since the snapshot is missing")
+	}
+	if got := second.mock.callCount.Load(); got != 0 {
+		t.Errorf("second run HintRead calls = %d, want 0", got)
+	}
+	stats := second.cache.Stats()
+	if stats.SnapshotHits != 1 || !stats.FullyLoaded || stats.HintCount != HintBulkFetchLimit+11 {
+		t.Errorf("unexpected stats after restore: %+v", stats)
+	}
+}
+
+func TestHintSnapshot_FullLoadFallbacks(t *testing.T) {
+	hints := makeHints(20)
+	cases := map[string]struct {
+		ttl   time.Duration
+		api   []wallarm.ActionBody // hints of the second run
+		setup func(t *testing.T, path string)
+	}{
+		// A deletion is invisible to the delta query; the count catches it.
+		"deleted since": {ttl: time.Hour, api: hints[1:]},
+		"expired":       {ttl: time.Nanosecond, api: hints},
+		"corrupt": {ttl: time.Hour, api: hints, setup: func(t *testing.T, path string) {
+			if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			first := newSnapshotRun(t, dir, time.Hour, hints, nil)
+			if err := first.cache.LoadAll(1, first.mock); err != nil {
+				t.Fatalf("LoadAll: %v", err)
+			}
+			if tc.setup != nil {
+				tc.setup(t, first.cache.snapshot.path(1))
+			}
+
+			second := newSnapshotRun(t, dir, tc.ttl, tc.api, nil)
+			if err := second.cache.LoadAll(1, second.mock); err != nil {
+				t.Fatalf("LoadAll: %v", err)
+			}
+			if got := second.mock.callCount.Load(); got != 1 {
+				t.Errorf("HintRead calls = %d, want a full load (1)", got)
+			}
+			stats := second.cache.Stats()
+			if stats.SnapshotHits != 0 || stats.HintCount != len(tc.api) {
+				t.Errorf("unexpected stats: %+v", stats)
+			}
+		})
+	}
+}
+
+func TestHintSnapshot_InvalidateRevalidatesWithDelta(t *testing.T) {
+	hints := makeHints(HintBulkFetchLimit + 10)
+	mock := &mockHintAPI{hints: hints}
+	var delta []wallarm.ActionBody
+	var deltaErr error
+	var since []time.Time
+	cache := NewHintCache()
+	cache.SetConcurrency(func(int) (int, error) { return len(mock.hints), nil }, 1)
+	cache.SetSnapshot(NewHintSnapshotStore(t.TempDir(), time.Hour, "https://api.wallarm.com", func(_ int, t time.Time) ([]wallarm.ActionBody, error) {
+		since = append(since, t)
+		return delta, deltaErr
+	}))
+	if err := cache.LoadAll(1, mock); err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	fetchedAt := cache.snapshotFetchedAt
+
+	// One hint deleted, one updated since the load.
+	deleted := mock.hints[0].ID
+	updated := mock.hints[5]
+	updated.Mode = "block"
+	mock.hints = mock.hints[1:]
+	delta = []wallarm.ActionBody{updated}
+	cache.Invalidate("HintDelete", deleted)
+
+	calls := mock.callCount.Load()
+	if err := cache.LoadAll(1, mock); err != nil {
+		t.Fatalf("LoadAll after Invalidate: %v", err)
+	}
+	if got := mock.callCount.Load(); got != calls {
+		t.Errorf("HintRead calls after Invalidate = %d, want none", got-calls)
+	}
+	if len(since) != 1 || !since[0].Equal(fetchedAt.Add(-hintSnapshotDeltaSlack)) {
+		t.Errorf("delta since = %v, want %v", since, fetchedAt.Add(-hintSnapshotDeltaSlack))
+	}
+	if h, _ := cache.GetOrFetch(deleted, 1, mock); h != nil {
+		t.Error("deleted hint is still cached")
+	}
+	if h, _ := cache.GetOrFetch(updated.ID, 1, mock); h == nil || h.Mode != "block" {
+		t.Errorf("expected the delta version of hint %d, got %+v", updated.ID, h)
+	}
+
+	// A failing delta falls back to a full load and is not retried.
+	deltaErr = fmt.Errorf("unsupported filter")
+	for range 2 {
+		cache.Invalidate("HintDelete")
+		calls = mock.callCount.Load()
+		if err := cache.LoadAll(1, mock); err != nil {
+			t.Fatalf("LoadAll: %v", err)
+		}
+		if got := mock.callCount.Load() - calls; got != 2 {
+			t.Errorf("HintRead calls = %d, want a full load (2)", got)
+		}
+	}
+	if len(since) != 2 {
+		t.Errorf("delta queries = %d, want 2", len(since))
+	}
+}
+
+func TestRawHintDelta_FilterIgnored(t *testing.T) {
+	since := time.Now().Add(-time.Hour)
+	raw, _ := testIntegrationCheckServer(t, http.StatusOK,
+		fmt.Sprintf(`{"status": 200, "body": [{"id": 7, "updated_at": %d}]}`, since.Add(-time.Hour).Unix()))
+	if _, err := rawHintDelta(raw)(1, since); err == nil {
+		t.Error("expected an error when the API ignores the updated_at filter")
+	}
+}
+
+func TestRawHintDelta(t *testing.T) {
+	raw, calls := testIntegrationCheckServer(t, http.StatusOK, `{"status": 200, "body": [{"id": 7, "type": "wallarm_mode"}]}`)
+	hints, err := rawHintDelta(raw)(1, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hints) != 1 || hints[0].ID != 7 {
+		t.Errorf("hints = %+v", hints)
+	}
+	if len(*calls) != 1 || (*calls)[0] != "POST /v1/objects/hint token" {
+		t.Errorf("calls: got %v", *calls)
+	}
+}
@@ -0,0 +1,125 @@
+package wallarm
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// newAPIHTTPClient builds the *http.Client under every API request from the
+// provider's proxy, TLS and timeout settings. The transports added by
+// ProviderConfigure (cassette, logging, throttling, auth) wrap its Transport.
+func newAPIHTTPClient(d *schema.ResourceData) (*http.Client, error) {
+	c := cleanhttp.DefaultPooledClient()
+	transport := c.Transport.(*http.Transport)
+
+	if v, ok := d.GetOk("proxy_url"); ok {
+		proxy, err := url.Parse(v.(string))
+		if err != nil {
+			return nil, fmt.Errorf("proxy_url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if v := d.Get("connect_timeout").(int); v > 0 {
+		transport.DialContext = (&net.Dialer{
+			Timeout:   time.Duration(v) * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+	}
+	c.Timeout = time.Duration(d.Get("request_timeout").(int)) * time.Second
+
+	tlsConfig, err := apiTLSConfig(d)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+	return c, nil
+}
+
+// apiTLSConfig returns the TLS settings of the API client: extra CAs on top
+// of the system pool, a client certificate for mutual TLS and, for test
+// setups only, disabled verification.
+func apiTLSConfig(d *schema.ResourceData) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if v, ok := d.GetOk("ca_bundle"); ok {
+		pem, err := readPEMSetting("ca_bundle", v.(string))
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			log.Printf("[WARN] Wallarm API: system certificate pool unavailable, trusting ca_bundle only: %s", err)
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("ca_bundle: no PEM certificates found")
+		}
+		cfg.RootCAs = pool
+	}
+
+	certSetting, hasCert := d.GetOk("client_cert")
+	keySetting, hasKey := d.GetOk("client_key")
+	if hasCert != hasKey {
+		return nil, errors.New("client_cert and client_key must be set together")
+	}
+	if hasCert {
+		certPEM, err := readPEMSetting("client_cert", certSetting.(string))
+		if err != nil {
+			return nil, err
+		}
+		keyPEM, err := readPEMSetting("client_key", keySetting.(string))
+		if err != nil {
+			return nil, err
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("client_cert/client_key: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if d.Get("insecure_skip_verify").(bool) {
+		log.Printf("[WARN] Wallarm API: insecure_skip_verify is set, TLS certificates of the API and proxy are not verified")
+		cfg.InsecureSkipVerify = true
+	}
+	return cfg, nil
+}
+
+// readPEMSetting returns the PEM a setting holds, either inline or in the
+// file it names.
+func readPEMSetting(name, v string) ([]byte, error) {
+	if strings.Contains(v, "-----BEGIN ") {
+		return []byte(v), nil
+	}
+	b, err := os.ReadFile(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return b, nil
+}
+
+// validateAPIHost rejects a plain-HTTP api_host unless allow_http is set:
+// the API token would travel unencrypted.
+func validateAPIHost(apiHost string, allowHTTP bool) error {
+	u, err := url.Parse(apiHost)
+	if err != nil {
+		return fmt.Errorf("api_host: %w", err)
+	}
+	if u.Scheme == "http" && !allowHTTP {
+		return fmt.Errorf("api_host %q uses plain HTTP; set allow_http = true for local test endpoints", apiHost)
+	}
+	return nil
+}
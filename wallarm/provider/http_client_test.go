@@ -0,0 +1,182 @@
+package wallarm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func testAPIHTTPClient(t *testing.T, raw map[string]any) (*http.Client, error) {
+	t.Helper()
+	return newAPIHTTPClient(schema.TestResourceDataRaw(t, Provider().Schema, raw))
+}
+
+func certPEM(cert *x509.Certificate) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+}
+
+// testClientCert returns a self-signed client certificate and key as PEM.
+func testClientCert(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestAPIHTTPClient_CABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer srv.Close()
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(bundle, []byte(certPEM(srv.Certificate())), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		raw     map[string]any
+		wantErr string
+	}{
+		{name: "system pool", raw: map[string]any{}, wantErr: "certificate"},
+		{name: "inline PEM", raw: map[string]any{"ca_bundle": certPEM(srv.Certificate())}},
+		{name: "file", raw: map[string]any{"ca_bundle": bundle}},
+		{name: "insecure", raw: map[string]any{"insecure_skip_verify": true}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := testAPIHTTPClient(t, tc.raw)
+			if err != nil {
+				t.Fatalf("newAPIHTTPClient: %v", err)
+			}
+			resp, err := c.Get(srv.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+				t.Errorf("GET err = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	if _, err := testAPIHTTPClient(t, map[string]any{"ca_bundle": "-----BEGIN nothing"}); err == nil {
+		t.Error("ca_bundle without certificates was accepted")
+	}
+}
+
+func TestAPIHTTPClient_ClientCert(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 || r.TLS.PeerCertificates[0].Subject.CommonName != "terraform" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	cert, key := testClientCert(t)
+	keyFile := filepath.Join(t.TempDir(), "client.key")
+	if err := os.WriteFile(keyFile, []byte(key), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := testAPIHTTPClient(t, map[string]any{
+		"ca_bundle":   certPEM(srv.Certificate()),
+		"client_cert": cert,
+		"client_key":  keyFile,
+	})
+	if err != nil {
+		t.Fatalf("newAPIHTTPClient: %v", err)
+	}
+	resp, err := c.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+
+	if _, err := testAPIHTTPClient(t, map[string]any{"client_cert": cert}); err == nil {
+		t.Error("client_cert without client_key was accepted")
+	}
+	if _, err := testAPIHTTPClient(t, map[string]any{"client_cert": cert, "client_key": cert}); err == nil {
+		t.Error("mismatched client_key was accepted")
+	}
+}
+
+func TestAPIHTTPClient_ProxyAndTimeout(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		if r.URL.Path == "/slow" {
+			time.Sleep(2 * time.Second)
+		}
+	}))
+	defer proxy.Close()
+
+	c, err := testAPIHTTPClient(t, map[string]any{"proxy_url": proxy.URL, "request_timeout": 1})
+	if err != nil {
+		t.Fatalf("newAPIHTTPClient: %v", err)
+	}
+	resp, err := c.Get("http://api.wallarm.invalid/v1/user")
+	if err != nil {
+		t.Fatalf("GET through proxy: %v", err)
+	}
+	resp.Body.Close()
+	if proxied != "http://api.wallarm.invalid/v1/user" {
+		t.Errorf("proxy saw %q", proxied)
+	}
+
+	if _, err := c.Get("http://api.wallarm.invalid/slow"); err == nil || !strings.Contains(err.Error(), "Timeout") {
+		t.Errorf("slow request err = %v, want a timeout", err)
+	}
+}
+
+func TestProviderConfigure_AllowHTTP(t *testing.T) {
+	srv := testFakeAPIServer(t)
+	p := Provider()
+	d := schema.TestResourceDataRaw(t, p.Schema, map[string]any{
+		"api_host":  srv.URL,
+		"api_token": testAccReplayToken,
+	})
+	_, diags := ProviderConfigure(context.Background(), d, p)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "allow_http") {
+		t.Errorf("plain-HTTP api_host without allow_http: diags = %v", diags)
+	}
+	if len(srv.Requests()) != 0 {
+		t.Errorf("requests sent to a rejected api_host: %v", srv.Requests())
+	}
+
+	testFakeAPIMeta(t, srv, nil) // sets allow_http
+}
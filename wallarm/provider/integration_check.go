@@ -0,0 +1,87 @@
+package wallarm
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/wallarm/wallarm-go"
+)
+
+const (
+	verifyFailureError   = "error"
+	verifyFailureWarning = "warning"
+)
+
+// checkIntegration asks the Cloud to deliver a test event through the
+// integration and returns the delivery error, if any.
+func checkIntegration(ctx context.Context, m any, clientID, integrationID int) error {
+	raw, err := apiRaw(m)
+	if err != nil {
+		return err
+	}
+	uri := fmt.Sprintf("/v2/integration/%d/test", integrationID)
+	return integrationCheckError(raw.do(ctx, http.MethodPost, uri, map[string]any{"clientid": clientID}, nil))
+}
+
+// integrationCheckError extracts the delivery error from the test endpoint's
+// error body ({"body": {"error": ...}} or {"body": "..."}). A 404 (unknown
+// integration) is returned as is.
+func integrationCheckError(err error) error {
+	var apiErr *wallarm.APIError
+	if !stderrors.As(err, &apiErr) || apiErr.StatusCode == http.StatusNotFound {
+		return err
+	}
+	var resp struct {
+		Body json.RawMessage `json:"body"`
+	}
+	if json.Unmarshal([]byte(apiErr.Body), &resp) == nil && len(resp.Body) > 0 {
+		var msg string
+		if json.Unmarshal(resp.Body, &msg) == nil && msg != "" {
+			return fmt.Errorf("test delivery failed (HTTP %d): %s", apiErr.StatusCode, msg)
+		}
+		var obj struct {
+			Error   string `json:"error"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(resp.Body, &obj) == nil {
+			if obj.Error != "" {
+				return fmt.Errorf("test delivery failed (HTTP %d): %s", apiErr.StatusCode, obj.Error)
+			}
+			if obj.Message != "" {
+				return fmt.Errorf("test delivery failed (HTTP %d): %s", apiErr.StatusCode, obj.Message)
+			}
+		}
+	}
+	return fmt.Errorf("test delivery failed: %w", err)
+}
+
+// verifyIntegrationOnApply runs checkIntegration after Create/Update when
+// verify_on_apply is set. verify_failure selects an error or a warning.
+func verifyIntegrationOnApply(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	if !d.Get("verify_on_apply").(bool) || d.Id() == "" {
+		return nil
+	}
+	clientID, err := retrieveClientID(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	integrationID := d.Get("integration_id").(int)
+	err = checkIntegration(ctx, m, clientID, integrationID)
+	if err == nil {
+		return nil
+	}
+	severity := diag.Error
+	if d.Get("verify_failure").(string) == verifyFailureWarning {
+		severity = diag.Warning
+	}
+	return diag.Diagnostics{{
+		Severity: severity,
+		Summary:  fmt.Sprintf("Integration %d failed the connectivity test", integrationID),
+		Detail:   err.Error(),
+	}}
+}
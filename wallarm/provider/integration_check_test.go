@@ -0,0 +1,132 @@
+package wallarm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// testIntegrationCheckServer serves POST /v2/integration/{id}/test with the
+// given status and body, and records the requests.
+func testIntegrationCheckServer(t *testing.T, status int, body string) (*rawAPI, *[]string) {
+	t.Helper()
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+r.Header.Get("X-WallarmAPI-Token"))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return newRawAPI(srv.Client(), srv.URL, http.Header{"X-Wallarmapi-Token": {"token"}}, "test"), &calls
+}
+
+func TestCheckIntegration(t *testing.T) {
+	raw, calls := testIntegrationCheckServer(t, http.StatusOK, `{"status": 200, "body": {"result": "ok"}}`)
+	meta := &ProviderMeta{RawAPI: raw}
+	if err := checkIntegration(context.Background(), meta, 1, 42); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*calls) != 1 || (*calls)[0] != "POST /v2/integration/42/test token" {
+		t.Errorf("calls: got %v", *calls)
+	}
+
+	for body, want := range map[string]string{
+		`{"status": 400, "body": {"error": "Slack returned 404 no_service"}}`: "test delivery failed (HTTP 400): Slack returned 404 no_service",
+		`{"status": 400, "body": "connection refused"}`:                       "test delivery failed (HTTP 400): connection refused",
+		`bad gateway`: "test delivery failed: HTTP Status: 400, Body: bad gateway",
+	} {
+		raw, _ := testIntegrationCheckServer(t, http.StatusBadRequest, body)
+		err := checkIntegration(context.Background(), &ProviderMeta{RawAPI: raw}, 1, 42)
+		if err == nil || err.Error() != want {
+			t.Errorf("body %s: got %v, want %q", body, err, want)
+		}
+	}
+}
+
+func TestVerifyIntegrationOnApply(t *testing.T) {
+	raw, calls := testIntegrationCheckServer(t, http.StatusBadRequest, `{"body": {"error": "invalid token"}}`)
+	meta := &ProviderMeta{RawAPI: raw, DefaultClientID: 1}
+
+	for _, tc := range []struct {
+		verify   bool
+		failure  string
+		severity diag.Severity
+		calls    int
+	}{
+		{verify: false, calls: 0},
+		{verify: true, failure: verifyFailureError, severity: diag.Error, calls: 1},
+		{verify: true, failure: verifyFailureWarning, severity: diag.Warning, calls: 1},
+	} {
+		*calls = nil
+		d := resourceWallarmSplunk().TestResourceData()
+		d.SetId("1/splunk/42")
+		d.Set("integration_id", 42)
+		d.Set("verify_on_apply", tc.verify)
+		d.Set("verify_failure", tc.failure)
+
+		diags := verifyIntegrationOnApply(context.Background(), d, meta)
+		if len(*calls) != tc.calls {
+			t.Errorf("verify=%v: %d test calls, want %d", tc.verify, len(*calls), tc.calls)
+		}
+		if tc.calls == 0 {
+			if len(diags) != 0 {
+				t.Errorf("verify=false: unexpected diagnostics %v", diags)
+			}
+			continue
+		}
+		if len(diags) != 1 || diags[0].Severity != tc.severity || !strings.Contains(diags[0].Detail, "invalid token") {
+			t.Errorf("failure=%s: got %+v", tc.failure, diags)
+		}
+	}
+}
+
+func TestIntegrationCheckDataSource(t *testing.T) {
+	raw, _ := testIntegrationCheckServer(t, http.StatusBadRequest, `{"body": "timeout"}`)
+	meta := &ProviderMeta{RawAPI: raw, DefaultClientID: 1}
+	r := dataSourceWallarmIntegrationCheck()
+
+	d := r.TestResourceData()
+	d.Set("integration_id", 42)
+	if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Get("success") != false || !strings.Contains(d.Get("error").(string), "timeout") || d.Get("checked_at") == "" {
+		t.Errorf("success=%v error=%v checked_at=%v", d.Get("success"), d.Get("error"), d.Get("checked_at"))
+	}
+
+	d.Set("fail_on_error", true)
+	if diags := r.ReadContext(context.Background(), d, meta); !diags.HasError() {
+		t.Error("fail_on_error: expected an error")
+	}
+
+	raw, _ = testIntegrationCheckServer(t, http.StatusNotFound, `{"body": "not found"}`)
+	d = r.TestResourceData()
+	d.Set("integration_id", 7)
+	if diags := r.ReadContext(context.Background(), d, &ProviderMeta{RawAPI: raw, DefaultClientID: 1}); !diags.HasError() ||
+		!strings.Contains(diags[0].Summary, "integration 7 not found") {
+		t.Errorf("unknown integration: got %v", diags)
+	}
+}
+
+func TestIntegrationCheckDataSource_ReadOnly(t *testing.T) {
+	raw, calls := testIntegrationCheckServer(t, http.StatusOK, `{"status": 200, "body": {"result": "ok"}}`)
+	raw.readOnly = true
+	r := dataSourceWallarmIntegrationCheck()
+
+	d := r.TestResourceData()
+	d.Set("integration_id", 42)
+	diags := r.ReadContext(context.Background(), d, &ProviderMeta{RawAPI: raw, DefaultClientID: 1})
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "read_only = true") {
+		t.Errorf("diags = %v, want a read_only error", diags)
+	}
+	if len(*calls) != 0 {
+		t.Errorf("test event sent in read-only mode: %v", *calls)
+	}
+}
@@ -13,7 +13,12 @@ import (
 	"github.com/wallarm/wallarm-go"
 )
 
-const eventTypeSIEM = "siem"
+const (
+	eventTypeSIEM = "siem"
+	// eventTypeHit is the user-facing alias of eventTypeSIEM. It is sent as
+	// "siem" and read back as "hit" when the configuration uses it.
+	eventTypeHit = "hit"
+)
 
 // isNotFoundError checks if the error is a Wallarm API 404 response.
 func isNotFoundError(err error) bool {
@@ -61,7 +66,7 @@ func validateWithHeadersOnlySiem() schema.CustomizeDiffFunc {
 				m := e.(map[string]any)
 				eventType, _ := m["event_type"].(string)
 				withHeaders, _ := m["with_headers"].(bool)
-				if withHeaders && eventType != eventTypeSIEM {
+				if withHeaders && eventType != eventTypeSIEM && eventType != eventTypeHit {
 					return fmt.Errorf("with_headers can only be set for the 'siem' event type, got event_type=%q", eventType)
 				}
 			}
@@ -70,155 +75,15 @@ func validateWithHeadersOnlySiem() schema.CustomizeDiffFunc {
 	)
 }
 
-// TODO: add test — empty set per resource type returns defaults, populated events with hit→siem mapping, with_headers on siem
-func expandWallarmEventToIntEvents(d any, resourceType string) *[]wallarm.IntegrationEvents {
+// expandWallarmEventToIntEvents converts the event set into API events,
+// mapping the "hit" alias to "siem". An empty set sends every event of the
+// catalogue inactive.
+func expandWallarmEventToIntEvents(d any, catalogue []string) *[]wallarm.IntegrationEvents {
 	cfg := d.(*schema.Set).List()
 	events := []wallarm.IntegrationEvents{}
 	if len(cfg) == 0 || cfg[0] == nil {
-		var defaultEvents []map[string]any
-		switch resourceType {
-		case "email":
-			defaultEvents = []map[string]any{
-				{
-					"event_type": "system",
-					"active":     false},
-				{
-					"event_type": "aasm_report",
-					"active":     false},
-				{
-					"event_type": "api_discovery_hourly_changes_report",
-					"active":     false},
-				{
-					"event_type": "api_discovery_daily_changes_report",
-					"active":     false},
-				{
-					"event_type": "report_daily",
-					"active":     false},
-				{
-					"event_type": "report_weekly",
-					"active":     false},
-				{
-					"event_type": "report_monthly",
-					"active":     false},
-			}
-		case "data_dog", "insight_connect", "splunk", "sumo_logic", "web_hooks":
-			defaultEvents = []map[string]any{
-				{
-					"event_type": eventTypeSIEM,
-					"active":     false},
-				{
-					"event_type": "rules_and_triggers",
-					"active":     false},
-				{
-					"event_type": "number_of_requests_per_hour",
-					"active":     false},
-				{
-					"event_type": "security_issue_critical",
-					"active":     false},
-				{
-					"event_type": "security_issue_high",
-					"active":     false},
-				{
-					"event_type": "security_issue_medium",
-					"active":     false},
-				{
-					"event_type": "security_issue_low",
-					"active":     false},
-				{
-					"event_type": "security_issue_info",
-					"active":     false},
-				{
-					"event_type": "system",
-					"active":     false},
-			}
-		case "telegram":
-			defaultEvents = []map[string]any{
-				{
-					"event_type": "system",
-					"active":     false},
-				{
-					"event_type": "rules_and_triggers",
-					"active":     false},
-				{
-					"event_type": "security_issue_critical",
-					"active":     false},
-				{
-					"event_type": "security_issue_high",
-					"active":     false},
-				{
-					"event_type": "security_issue_medium",
-					"active":     false},
-				{
-					"event_type": "security_issue_low",
-					"active":     false},
-				{
-					"event_type": "security_issue_info",
-					"active":     false},
-				{
-					"event_type": "report_daily",
-					"active":     false},
-				{
-					"event_type": "report_weekly",
-					"active":     false},
-				{
-					"event_type": "report_monthly",
-					"active":     false},
-			}
-		case "ms_teams", "opsgenie", "pager_duty", "slack":
-			defaultEvents = []map[string]any{
-				{
-					"event_type": "system",
-					"active":     false},
-				{
-					"event_type": "rules_and_triggers",
-					"active":     false},
-				{
-					"event_type": "security_issue_critical",
-					"active":     false},
-				{
-					"event_type": "security_issue_high",
-					"active":     false},
-				{
-					"event_type": "security_issue_medium",
-					"active":     false},
-				{
-					"event_type": "security_issue_low",
-					"active":     false},
-				{
-					"event_type": "security_issue_info",
-					"active":     false},
-			}
-		default:
-			defaultEvents = []map[string]any{
-				{
-					"event_type": "system",
-					"active":     false},
-				{
-					"event_type": "rules_and_triggers",
-					"active":     false},
-				{
-					"event_type": "security_issue_critical",
-					"active":     false},
-				{
-					"event_type": "security_issue_high",
-					"active":     false},
-				{
-					"event_type": "security_issue_medium",
-					"active":     false},
-				{
-					"event_type": "security_issue_low",
-					"active":     false},
-				{
-					"event_type": "security_issue_info",
-					"active":     false},
-			}
-		}
-		for _, v := range defaultEvents {
-			event := wallarm.IntegrationEvents{
-				Event:  v["event_type"].(string),
-				Active: v["active"].(bool),
-			}
-			events = append(events, event)
+		for _, name := range catalogue {
+			events = append(events, wallarm.IntegrationEvents{Event: name})
 		}
 		return &events
 	}
@@ -229,7 +94,7 @@ func expandWallarmEventToIntEvents(d any, resourceType string) *[]wallarm.Integr
 		e := wallarm.IntegrationEvents{}
 		event, ok := m["event_type"]
 		if ok {
-			if event.(string) == "hit" {
+			if event.(string) == eventTypeHit {
 				e.Event = eventTypeSIEM
 			} else {
 				e.Event = event.(string)
@@ -251,3 +116,125 @@ func expandWallarmEventToIntEvents(d any, resourceType string) *[]wallarm.Integr
 	}
 	return &events
 }
+
+// setIntegrationMetadata sets the fields every integration shares from the API
+// object. active mirrors the server flag so console toggles show as drift.
+func setIntegrationMetadata(d *schema.ResourceData, obj *wallarm.IntegrationObject, clientID int) {
+	d.Set("integration_id", obj.ID)
+	d.Set("is_active", obj.Active)
+	d.Set("active", obj.Active)
+	d.Set("name", obj.Name)
+	d.Set("created_by", obj.CreatedBy)
+	d.Set("type", obj.Type)
+	d.Set("client_id", clientID)
+}
+
+// flattenIntegrationEvents converts API event subscriptions into the event set.
+//
+// The API returns every event the integration type knows, subscribed or not,
+// so the result holds the event types already in state (with the API's active
+// flag) plus any other event the API reports as active. Inactive events that
+// were never configured are left out: they are equivalent to not subscribing.
+// "siem" is rendered as "hit" when the prior state used the alias. withHeaders
+// tells whether the resource's event block has the with_headers attribute.
+func flattenIntegrationEvents(d *schema.ResourceData, obj *wallarm.IntegrationObject, withHeaders bool) []any {
+	prior := d.Get("event").(*schema.Set).List()
+
+	configured := make(map[string]string, len(prior))
+	for _, e := range prior {
+		m := e.(map[string]any)
+		name, _ := m["event_type"].(string)
+		apiName := name
+		if name == eventTypeHit {
+			apiName = eventTypeSIEM
+		}
+		configured[apiName] = name
+	}
+
+	result := make([]any, 0, len(obj.Events))
+	for _, e := range obj.Events {
+		name, ok := configured[e.Event]
+		if !ok {
+			if !e.Active {
+				continue
+			}
+			name = e.Event
+		}
+		event := map[string]any{
+			"event_type": name,
+			"active":     e.Active,
+		}
+		if withHeaders {
+			event["with_headers"] = e.WithHeaders != nil && *e.WithHeaders
+		}
+		result = append(result, event)
+	}
+	return result
+}
+
+// isMaskedSecret reports whether the API returned a secret in masked form.
+func isMaskedSecret(v string) bool {
+	return strings.Contains(v, "***") || strings.Contains(v, "•")
+}
+
+// integrationSecret returns the value to store for a secret read from the
+// API: the configured value when the API masks or omits it, the API value
+// otherwise (so a secret changed in the console is seen as drift).
+func integrationSecret(d *schema.ResourceData, key, apiValue string) string {
+	if apiValue == "" || isMaskedSecret(apiValue) {
+		return d.Get(key).(string)
+	}
+	return apiValue
+}
+
+// integrationTargetMap returns the API target as a JSON object, or nil.
+func integrationTargetMap(obj *wallarm.IntegrationObject) map[string]any {
+	m, _ := obj.Target.(map[string]any)
+	return m
+}
+
+// integrationTargetString returns the API target as a string, or "".
+func integrationTargetString(obj *wallarm.IntegrationObject) string {
+	s, _ := obj.Target.(string)
+	return s
+}
+
+// targetString and targetInt read scalar target fields decoded from JSON.
+func targetString(target map[string]any, key string) string {
+	s, _ := target[key].(string)
+	return s
+}
+
+func targetInt(target map[string]any, key string) (int, bool) {
+	f, ok := target[key].(float64)
+	return int(f), ok
+}
+
+// expandStringTarget and flattenStringTarget handle types whose API target is
+// a single string attribute (a webhook URL or an integration key).
+func expandStringTarget(key string) func(*schema.ResourceData) any {
+	return func(d *schema.ResourceData) any {
+		return d.Get(key).(string)
+	}
+}
+
+func flattenStringTarget(key string) func(*schema.ResourceData, *wallarm.IntegrationObject) {
+	return func(d *schema.ResourceData, obj *wallarm.IntegrationObject) {
+		d.Set(key, integrationSecret(d, key, integrationTargetString(obj)))
+	}
+}
+
+// expandTokenAPITarget and flattenTokenAPITarget handle types whose API target
+// is {"token", "api"} (api_token, api_url).
+func expandTokenAPITarget(d *schema.ResourceData) any {
+	return &wallarm.IntegrationTokenAPITarget{
+		Token: d.Get("api_token").(string),
+		API:   d.Get("api_url").(string),
+	}
+}
+
+func flattenTokenAPITarget(d *schema.ResourceData, obj *wallarm.IntegrationObject) {
+	target := integrationTargetMap(obj)
+	d.Set("api_url", integrationSecret(d, "api_url", targetString(target, "api")))
+	d.Set("api_token", integrationSecret(d, "api_token", targetString(target, "token")))
+}
@@ -1,12 +1,149 @@
 package wallarm
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	wallarm "github.com/wallarm/wallarm-go"
 )
 
+// mockIntegrationAPI serves IntegrationRead from a fixed JSON object.
+type mockIntegrationAPI struct {
+	wallarm.API
+	object string
+}
+
+func (m *mockIntegrationAPI) IntegrationRead(_ int, _ int) (*wallarm.IntegrationObject, error) {
+	var obj wallarm.IntegrationObject
+	if err := json.Unmarshal([]byte(m.object), &obj); err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+
+func testIntegrationEvents(t *testing.T, d *schema.ResourceData) map[string]map[string]any {
+	t.Helper()
+	events := map[string]map[string]any{}
+	for _, e := range d.Get("event").(*schema.Set).List() {
+		m := e.(map[string]any)
+		events[m["event_type"].(string)] = m
+	}
+	return events
+}
+
+func TestWebhookRead_Flatten(t *testing.T) {
+	meta := &ProviderMeta{Client: &mockIntegrationAPI{object: `{
+		"id": 5, "active": false, "name": "hook", "type": "web_hooks", "created_by": "ops@example.com",
+		"target": {"url": "https://example.com/new", "http_method": "PUT", "format": "jsonl",
+			"headers": {"X-Token": "******", "X-Env": "prod"}, "ca_file": "", "ca_verify": false,
+			"timeout": 30, "open_timeout": 5},
+		"events": [
+			{"event": "siem", "active": true, "with_headers": true},
+			{"event": "system", "active": false},
+			{"event": "rules_and_triggers", "active": true},
+			{"event": "security_issue_low", "active": false}
+		]}`}}
+
+	d := resourceWallarmWebhook().TestResourceData()
+	d.SetId("1/web_hooks/5")
+	d.Set("client_id", 1)
+	d.Set("integration_id", 5)
+	d.Set("webhook_url", "https://example.com/old")
+	d.Set("headers", map[string]any{"X-Token": "secret", "X-Env": "dev"})
+	d.Set("event", []any{
+		map[string]any{"event_type": "hit", "active": true, "with_headers": false},
+		map[string]any{"event_type": "system", "active": true},
+	})
+
+	if diags := resourceWallarmWebhook().ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if d.Get("active") != false || d.Get("name") != "hook" || d.Get("webhook_url") != "https://example.com/new" {
+		t.Errorf("metadata: active=%v name=%v webhook_url=%v", d.Get("active"), d.Get("name"), d.Get("webhook_url"))
+	}
+	if d.Get("http_method") != "PUT" || d.Get("format") != "jsonl" || d.Get("ca_verify") != false ||
+		d.Get("timeout") != 30 || d.Get("open_timeout") != 5 {
+		t.Errorf("target: method=%v format=%v ca_verify=%v timeout=%v open_timeout=%v",
+			d.Get("http_method"), d.Get("format"), d.Get("ca_verify"), d.Get("timeout"), d.Get("open_timeout"))
+	}
+	headers := d.Get("headers").(map[string]any)
+	if headers["X-Token"] != "secret" || headers["X-Env"] != "prod" {
+		t.Errorf("headers: got %v", headers)
+	}
+
+	events := testIntegrationEvents(t, d)
+	if len(events) != 3 {
+		t.Fatalf("events: got %v, want hit, system and rules_and_triggers", events)
+	}
+	if e := events["hit"]; e == nil || e["active"] != true || e["with_headers"] != true {
+		t.Errorf("siem must be read back as the hit alias: %v", events)
+	}
+	if events["system"]["active"] != false {
+		t.Errorf("system: console deactivation not detected: %v", events["system"])
+	}
+	if events["rules_and_triggers"]["active"] != true {
+		t.Errorf("rules_and_triggers: active event added in console not detected")
+	}
+}
+
+func TestSlackRead_MaskedSecret(t *testing.T) {
+	meta := &ProviderMeta{Client: &mockIntegrationAPI{object: `{
+		"id": 7, "active": true, "name": "slack", "type": "slack",
+		"target": "https://hooks.slack.com/services/****",
+		"events": [{"event": "system", "active": true}]}`}}
+
+	d := resourceWallarmSlack().TestResourceData()
+	d.SetId("1/slack/7")
+	d.Set("client_id", 1)
+	d.Set("integration_id", 7)
+	d.Set("webhook_url", "https://hooks.slack.com/services/T0/B0/XYZ")
+
+	if diags := resourceWallarmSlack().ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if got := d.Get("webhook_url"); got != "https://hooks.slack.com/services/T0/B0/XYZ" {
+		t.Errorf("masked webhook_url must keep the configured value, got %v", got)
+	}
+	if events := testIntegrationEvents(t, d); len(events) != 1 || events["system"]["active"] != true {
+		t.Errorf("events: got %v", events)
+	}
+}
+
+func TestEmailRead_Emails(t *testing.T) {
+	meta := &ProviderMeta{Client: &mockIntegrationAPI{object: `{
+		"id": 9, "active": true, "name": "mail", "type": "email",
+		"target": ["a@example.com", "b@example.com"],
+		"events": [{"event": "report_daily", "active": true}, {"event": "system", "active": false}]}`}}
+
+	d := resourceWallarmEmail().TestResourceData()
+	d.SetId("1/email/9")
+	d.Set("client_id", 1)
+	d.Set("integration_id", 9)
+
+	if diags := resourceWallarmEmail().ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	emails := d.Get("emails").([]any)
+	if len(emails) != 2 || emails[0] != "a@example.com" {
+		t.Errorf("emails: got %v", emails)
+	}
+	if events := testIntegrationEvents(t, d); len(events) != 1 || events["report_daily"] == nil {
+		t.Errorf("import must only read active events, got %v", events)
+	}
+}
+
+func TestIsMaskedSecret(t *testing.T) {
+	for v, want := range map[string]bool{"": false, "abc": false, "ab***cd": true, "••••": true} {
+		if got := isMaskedSecret(v); got != want {
+			t.Errorf("isMaskedSecret(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
+
 func TestIsNotFoundError_404(t *testing.T) {
 	err := &wallarm.APIError{StatusCode: 404, Body: "not found"}
 	if !isNotFoundError(err) {
@@ -0,0 +1,371 @@
+package wallarm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/wallarm/wallarm-go"
+)
+
+// integrationSpec declares one integration type. newIntegrationResource turns
+// it into a wallarm_integration_* resource; everything that differs between
+// integration types lives here.
+type integrationSpec struct {
+	// APIType is the Cloud's integration type, also the type segment of the
+	// resource and import IDs. Empty for the generic wallarm_integration
+	// resource, which takes it from the type attribute.
+	APIType string
+	// DefaultName is the default of the name attribute.
+	DefaultName string
+	// Events is the event catalogue. It validates event_type, and events left
+	// out of the configuration are sent inactive on create. A catalogue with
+	// "siem" also accepts the "hit" alias and with_headers.
+	Events []string
+	// EventsRequired makes the event set required (at least one event).
+	EventsRequired bool
+	// EventActiveDefault is the default of event.active.
+	EventActiveDefault bool
+	// Transport is the connection schema merged into the resource schema.
+	Transport map[string]*schema.Schema
+	// Secrets lists the Transport attributes holding credentials. They must be
+	// Sensitive; the API may return them masked, and Read keeps the configured
+	// value then (see integrationSecret).
+	Secrets []string
+	// ExpandTarget builds the API target from the configuration. Types whose
+	// target cannot be changed after create (telegram) leave it nil.
+	ExpandTarget func(d *schema.ResourceData) any
+	// FlattenTarget sets the Transport attributes from the API object.
+	FlattenTarget func(d *schema.ResourceData, obj *wallarm.IntegrationObject)
+	// Create replaces the default IntegrationCreate call for types with a
+	// dedicated create endpoint.
+	Create func(spec *integrationSpec, client wallarm.API, d *schema.ResourceData, clientID int) (*wallarm.IntegrationCreateResp, error)
+}
+
+// Event catalogues shared by several integration types.
+var (
+	// siemIntegrationEvents is the catalogue of SIEM and log-shipping types.
+	siemIntegrationEvents = []string{
+		eventTypeSIEM,
+		"rules_and_triggers",
+		"number_of_requests_per_hour",
+		"security_issue_critical",
+		"security_issue_high",
+		"security_issue_medium",
+		"security_issue_low",
+		"security_issue_info",
+		"system",
+	}
+	// chatIntegrationEvents is the catalogue of chat and incident types.
+	chatIntegrationEvents = []string{
+		"system",
+		"rules_and_triggers",
+		"security_issue_critical",
+		"security_issue_high",
+		"security_issue_medium",
+		"security_issue_low",
+		"security_issue_info",
+	}
+)
+
+// integrationSpecs is the registry of typed integration resources, keyed by
+// resource name.
+var integrationSpecs = map[string]*integrationSpec{
+	"wallarm_integration_data_dog":       &dataDogIntegration,
+	"wallarm_integration_email":          &emailIntegration,
+	"wallarm_integration_insightconnect": &insightConnectIntegration,
+	"wallarm_integration_opsgenie":       &opsGenieIntegration,
+	"wallarm_integration_pagerduty":      &pagerDutyIntegration,
+	"wallarm_integration_slack":          &slackIntegration,
+	"wallarm_integration_splunk":         &splunkIntegration,
+	"wallarm_integration_sumologic":      &sumoLogicIntegration,
+	"wallarm_integration_teams":          &teamsIntegration,
+	"wallarm_integration_telegram":       &telegramIntegration,
+	"wallarm_integration_webhook":        &webhookIntegration,
+}
+
+// siem reports whether the type accepts the siem event.
+func (spec *integrationSpec) siem() bool {
+	return spec.APIType == "" || slices.Contains(spec.Events, eventTypeSIEM)
+}
+
+// apiType returns the API type of the integration d describes.
+func (spec *integrationSpec) apiType(d *schema.ResourceData) string {
+	if spec.APIType != "" {
+		return spec.APIType
+	}
+	return d.Get("type").(string)
+}
+
+// transportKeys returns the Transport attribute names.
+func (spec *integrationSpec) transportKeys() []string {
+	keys := make([]string, 0, len(spec.Transport))
+	for k := range spec.Transport {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func (spec *integrationSpec) eventSchema() *schema.Schema {
+	eventType := &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: !spec.EventsRequired,
+		Required: spec.EventsRequired,
+	}
+	if len(spec.Events) > 0 {
+		allowed := slices.Clone(spec.Events)
+		if spec.siem() {
+			allowed = slices.Insert(allowed, slices.Index(allowed, eventTypeSIEM)+1, eventTypeHit)
+		}
+		eventType.ValidateFunc = validation.StringInSlice(allowed, false)
+	}
+
+	elem := map[string]*schema.Schema{
+		"event_type": eventType,
+		"active": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  spec.EventActiveDefault,
+		},
+	}
+	if spec.siem() {
+		elem["with_headers"] = &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Send requests with headers. Only applicable to the 'siem' event type.",
+		}
+	}
+
+	s := &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: !spec.EventsRequired,
+		Required: spec.EventsRequired,
+		Elem:     &schema.Resource{Schema: elem},
+	}
+	if len(spec.Events) > 0 {
+		s.MaxItems = len(spec.Events)
+	}
+	if spec.EventsRequired {
+		s.MinItems = 1
+	}
+	return s
+}
+
+// newIntegrationResource builds an integration resource from its spec.
+func newIntegrationResource(spec *integrationSpec) *schema.Resource {
+	s := map[string]*schema.Schema{
+		"client_id": defaultClientIDWithValidationSchema,
+
+		"active": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+
+		"integration_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+
+		"created_by": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+
+		"type": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+
+		"is_active": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+
+		"name": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  spec.DefaultName,
+		},
+
+		"event": spec.eventSchema(),
+
+		"verify_on_apply": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Send a test event through the integration after create and update.",
+		},
+
+		"verify_failure": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      verifyFailureError,
+			ValidateFunc: validation.StringInSlice([]string{verifyFailureError, verifyFailureWarning}, false),
+			Description:  "Whether a failed verify_on_apply test fails the apply or only warns.",
+		},
+	}
+	for k, v := range spec.Transport {
+		s[k] = v
+	}
+
+	r := &schema.Resource{
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+			return integrationCreate(ctx, spec, d, m)
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+			return integrationRead(ctx, spec, d, m)
+		},
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+			return integrationUpdate(ctx, spec, d, m)
+		},
+		DeleteContext: integrationDelete,
+		Schema:        s,
+	}
+	if spec.APIType != "" {
+		r.Importer = &schema.ResourceImporter{StateContext: importIntegration(spec.APIType)}
+	}
+	if spec.siem() {
+		r.CustomizeDiff = validateWithHeadersOnlySiem()
+	}
+	return r
+}
+
+func integrationCreate(ctx context.Context, spec *integrationSpec, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := apiClient(m)
+	clientID, err := retrieveClientID(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var createRes *wallarm.IntegrationCreateResp
+	if spec.Create != nil {
+		createRes, err = spec.Create(spec, client, d, clientID)
+	} else {
+		body := wallarm.IntegrationCreate{
+			Name:     d.Get("name").(string),
+			Active:   d.Get("active").(bool),
+			Target:   spec.ExpandTarget(d),
+			Events:   expandWallarmEventToIntEvents(d.Get("event"), spec.Events),
+			Type:     spec.apiType(d),
+			Clientid: clientID,
+		}
+		createRes, err = client.IntegrationCreate(&body)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("integration_id", createRes.Body.ID)
+	d.SetId(fmt.Sprintf("%d/%s/%d", clientID, createRes.Body.Type, createRes.Body.ID))
+
+	if diags := integrationRead(ctx, spec, d, m); diags.HasError() {
+		return diags
+	}
+	return verifyIntegrationOnApply(ctx, d, m)
+}
+
+func integrationRead(_ context.Context, spec *integrationSpec, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := apiClient(m)
+	clientID, err := retrieveClientID(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	obj, err := client.IntegrationRead(clientID, d.Get("integration_id").(int))
+	if err != nil {
+		if isNotFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	setIntegrationMetadata(d, obj, clientID)
+	if d.Get("verify_failure").(string) == "" {
+		// Imported: the provider-only settings take their defaults.
+		d.Set("verify_on_apply", false)
+		d.Set("verify_failure", verifyFailureError)
+	}
+	if spec.FlattenTarget != nil {
+		spec.FlattenTarget(d, obj)
+	}
+	if err := d.Set("event", flattenIntegrationEvents(d, obj, spec.siem())); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// integrationUpdate sends the full configuration when the events change (the
+// API requires it) and a partial update of name, active and target otherwise.
+// Types without ExpandTarget send events in the partial update.
+func integrationUpdate(ctx context.Context, spec *integrationSpec, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := apiClient(m)
+	clientID, err := retrieveClientID(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	obj, err := client.IntegrationRead(clientID, d.Get("integration_id").(int))
+	if err != nil {
+		if isNotFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	var updateRes *wallarm.IntegrationCreateResp
+	if d.HasChange("event") && spec.ExpandTarget != nil {
+		fullBody := wallarm.IntegrationCreate{
+			Name:   d.Get("name").(string),
+			Active: d.Get("active").(bool),
+			Target: spec.ExpandTarget(d),
+			Type:   spec.apiType(d),
+			Events: expandWallarmEventToIntEvents(d.Get("event"), spec.Events),
+		}
+		updateRes, err = client.IntegrationUpdate(&fullBody, obj.ID)
+	} else {
+		updateBody := make(map[string]any)
+		if d.HasChange("name") {
+			updateBody["name"] = d.Get("name").(string)
+		}
+		if d.HasChange("active") {
+			updateBody["active"] = d.Get("active").(bool)
+		}
+		if d.HasChange("event") {
+			updateBody["events"] = expandWallarmEventToIntEvents(d.Get("event"), spec.Events)
+		}
+		if spec.ExpandTarget != nil && d.HasChanges(spec.transportKeys()...) {
+			updateBody["target"] = spec.ExpandTarget(d)
+		}
+		if len(updateBody) > 0 {
+			updateRes, err = client.IntegrationPartialUpdate(obj.ID, updateBody)
+		}
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if updateRes != nil {
+		d.Set("integration_id", updateRes.Body.ID)
+		d.SetId(fmt.Sprintf("%d/%s/%d", clientID, updateRes.Body.Type, updateRes.Body.ID))
+	}
+
+	if diags := integrationRead(ctx, spec, d, m); diags.HasError() {
+		return diags
+	}
+	return verifyIntegrationOnApply(ctx, d, m)
+}
+
+func integrationDelete(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := apiClient(m)
+	integrationID := d.Get("integration_id").(int)
+	if err := client.IntegrationDelete(integrationID); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package wallarm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestIntegrationSpecs_Resources(t *testing.T) {
+	provider := Provider()
+	for name, spec := range integrationSpecs {
+		r := newIntegrationResource(spec)
+		if err := r.InternalValidate(nil, true); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if provider.ResourcesMap[name] == nil {
+			t.Errorf("%s: not registered in the provider", name)
+		}
+		if r.Importer == nil {
+			t.Errorf("%s: no importer", name)
+		}
+		for _, key := range spec.Secrets {
+			if s := spec.Transport[key]; s == nil || !s.Sensitive {
+				t.Errorf("%s: secret %q must be a Sensitive transport attribute", name, key)
+			}
+		}
+		eventType := r.Schema["event"].Elem.(*schema.Resource).Schema["event_type"]
+		for _, event := range spec.Events {
+			if _, errs := eventType.ValidateFunc(event, "event_type"); len(errs) > 0 {
+				t.Errorf("%s: catalogue event %q rejected: %v", name, event, errs)
+			}
+		}
+	}
+	if err := resourceWallarmIntegration().InternalValidate(nil, true); err != nil {
+		t.Errorf("wallarm_integration: %v", err)
+	}
+}
+
+func TestIntegrationSpec_EventSchema(t *testing.T) {
+	webhook := webhookIntegration.eventSchema()
+	if _, ok := webhook.Elem.(*schema.Resource).Schema["with_headers"]; !ok {
+		t.Error("siem types must accept with_headers")
+	}
+	if _, errs := webhook.Elem.(*schema.Resource).Schema["event_type"].ValidateFunc(eventTypeHit, "event_type"); len(errs) > 0 {
+		t.Errorf("siem types must accept the hit alias: %v", errs)
+	}
+
+	email := emailIntegration.eventSchema()
+	if !email.Required || email.MinItems != 1 || email.MaxItems != len(emailIntegration.Events) {
+		t.Errorf("email event set: required=%v min=%d max=%d", email.Required, email.MinItems, email.MaxItems)
+	}
+	if _, ok := email.Elem.(*schema.Resource).Schema["with_headers"]; ok {
+		t.Error("non-siem types must not accept with_headers")
+	}
+	if got := dataDogIntegration.eventSchema().Elem.(*schema.Resource).Schema["active"].Default; got != false {
+		t.Errorf("data_dog event.active default: got %v, want false", got)
+	}
+}
+
+func TestExpandWallarmEventToIntEvents_Catalogue(t *testing.T) {
+	d := resourceWallarmSlack().TestResourceData()
+	events := *expandWallarmEventToIntEvents(d.Get("event"), slackIntegration.Events)
+	if len(events) != len(chatIntegrationEvents) {
+		t.Fatalf("empty event set must send the whole catalogue, got %v", events)
+	}
+	for i, e := range events {
+		if e.Event != chatIntegrationEvents[i] || e.Active {
+			t.Errorf("events[%d]: got %+v, want inactive %q", i, e, chatIntegrationEvents[i])
+		}
+	}
+}
+
+func TestIntegrationRead_Generic(t *testing.T) {
+	meta := &ProviderMeta{Client: &mockIntegrationAPI{object: `{
+		"id": 11, "active": true, "name": "fluent", "type": "fluentd",
+		"target": {"url": "https://logs.example.com", "token": "****", "timeout": 15, "added": "x"},
+		"events": [{"event": "siem", "active": true}]}`}}
+
+	d := resourceWallarmIntegration().TestResourceData()
+	d.SetId("1/fluentd/11")
+	d.Set("client_id", 1)
+	d.Set("integration_id", 11)
+	d.Set("type", "fluentd")
+	d.Set("config", map[string]any{"url": "https://old.example.com", "token": "secret", "timeout": "15"})
+
+	if diags := resourceWallarmIntegration().ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	want := map[string]any{"url": "https://logs.example.com", "token": "secret", "timeout": "15"}
+	if got := d.Get("config").(map[string]any); !reflect.DeepEqual(got, want) {
+		t.Errorf("config: got %v, want %v", got, want)
+	}
+	if d.Get("type") != "fluentd" || d.Get("name") != "fluent" {
+		t.Errorf("metadata: type=%v name=%v", d.Get("type"), d.Get("name"))
+	}
+}
+
+func TestImportGenericIntegration(t *testing.T) {
+	meta := &ProviderMeta{Client: &mockIntegrationAPI{object: `{
+		"id": 12, "active": true, "name": "hook", "type": "custom",
+		"target": {"url": "https://example.com", "retries": 3},
+		"events": []}`}}
+
+	r := resourceWallarmIntegration()
+	d := r.TestResourceData()
+	d.SetId("1/custom/12")
+	if _, err := r.Importer.StateContext(context.Background(), d, meta); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if d.Get("type") != "custom" || d.Get("integration_id") != 12 || d.Get("client_id") != 1 {
+		t.Fatalf("import: type=%v integration_id=%v client_id=%v", d.Get("type"), d.Get("integration_id"), d.Get("client_id"))
+	}
+	if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if got := d.Get("config").(map[string]any); !reflect.DeepEqual(got, map[string]any{"url": "https://example.com"}) {
+		t.Errorf("import must read string config fields, got %v", got)
+	}
+
+	d.SetId("1/12")
+	if _, err := r.Importer.StateContext(context.Background(), d, meta); err == nil {
+		t.Error("expected an error for an ID without a type segment")
+	}
+}
+
+func TestExpandGenericTarget(t *testing.T) {
+	d := resourceWallarmIntegration().TestResourceData()
+	d.Set("config", map[string]any{"url": "https://example.com", "timeout": "1"})
+	d.Set("config_json", `{"timeout": 30, "verify": true}`)
+	want := map[string]any{"url": "https://example.com", "timeout": float64(30), "verify": true}
+	if got := expandGenericTarget(d); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	d = resourceWallarmIntegration().TestResourceData()
+	d.Set("target", "key")
+	if got := expandGenericTarget(d); got != "key" {
+		t.Errorf("scalar target: got %v", got)
+	}
+}
@@ -11,7 +11,7 @@ import (
 )
 
 // allRuleTypes is the full list of IP list rule types.
-var allRuleTypes = []string{ruleTypeSubnet, "location", "datacenter", "proxy_type"}
+var allRuleTypes = []string{ruleTypeSubnet, ruleTypeLocation, ruleTypeDatacenter, ruleTypeProxyType, ruleTypeASN}
 
 // IPCacheEntry stores the API group ID and metadata for a single IP list value.
 type IPCacheEntry struct {
@@ -19,6 +19,9 @@ type IPCacheEntry struct {
 	RuleType       string
 	RawValue       string // API value (e.g. "1.2.3.4/32")
 	ApplicationIDs []int  // Application IDs assigned to this entry
+	ExpiredAt      int    // API expired_at (unix seconds, 0 = no expiry)
+	Reason         string // Free-text reason of the group
+	ValueCount     int    // Number of values in the group
 }
 
 // IPListCache provides a shared, thread-safe map of IP list values to their API group IDs.
@@ -149,6 +152,9 @@ func (c *IPListCache) RefreshUntilFound(
 				RuleType:       group.RuleType,
 				RawValue:       group.Values[0],
 				ApplicationIDs: group.ApplicationIDs,
+				ExpiredAt:      group.ExpiredAt,
+				Reason:         group.Reason,
+				ValueCount:     len(group.Values),
 			}
 			if m != nil {
 				m[val] = entry
@@ -254,6 +260,9 @@ func (c *IPListCache) loadRuleTypesLocked(client wallarm.API, listType wallarm.I
 			GroupID:        group.ID,
 			RuleType:       group.RuleType,
 			ApplicationIDs: group.ApplicationIDs,
+			ExpiredAt:      group.ExpiredAt,
+			Reason:         group.Reason,
+			ValueCount:     len(group.Values),
 		}
 		typeCounts[group.RuleType]++
 		for _, val := range group.Values {
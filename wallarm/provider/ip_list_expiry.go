@@ -0,0 +1,215 @@
+package wallarm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Renew policies for IP list expiry.
+const (
+	// ipListRenewNever keeps the expiry computed at Create for the lifetime of the
+	// resource. Only a change to time/time_format recomputes it; expired entries
+	// stay expired.
+	ipListRenewNever = "never"
+	// ipListRenewOnChange recomputes the expiry whenever the entries are rebuilt
+	// (reason, application, time, time_format) and re-adds expired entries.
+	ipListRenewOnChange = "on_change"
+	// ipListRenewAlways recomputes the expiry on every apply (sliding window).
+	ipListRenewAlways = "always"
+)
+
+var ipListRenewPolicies = []string{ipListRenewNever, ipListRenewOnChange, ipListRenewAlways}
+
+// ipListNoExpiry is an API expired_at without expiry. The provider never
+// sends it, but reads it as "never expires".
+const ipListNoExpiry = 0
+
+// ipListForeverYears is how far ahead Forever entries expire, the value the
+// provider has always sent for them.
+const ipListForeverYears = 100
+
+// parseExpireTime converts time_format + time into an absolute expired_at
+// (unix seconds) relative to now. Forever and a relative time of 0 expire
+// ipListForeverYears from now.
+func parseExpireTime(timeFormat, value string, now time.Time) (int, error) {
+	timeFormat = strings.ToLower(timeFormat)
+
+	switch timeFormat {
+	case "forever":
+		return int(now.AddDate(ipListForeverYears, 0, 0).Unix()), nil
+	case "rfc3339":
+		expireTime, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return 0, fmt.Errorf("cannot parse time to integer. must be the valid RFC3339 time when `time_format` equals `RFC3339`, got %v.\nExample: 2006-01-02T15:04:05+07:00", err)
+		}
+		return int(expireTime.Unix()), nil
+	case "minutes", "hours", "days", "weeks", "months":
+	default:
+		return 0, fmt.Errorf("unsupported time_format")
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse time to integer. must be the number when `time_format` equals `%s`, got %v",
+			strings.ToUpper(timeFormat[:1])+timeFormat[1:], err)
+	}
+	if n == 0 {
+		return int(now.AddDate(ipListForeverYears, 0, 0).Unix()), nil
+	}
+
+	var expireAt time.Time
+	switch timeFormat {
+	case "minutes":
+		expireAt = now.Add(time.Minute * time.Duration(n))
+	case "hours":
+		expireAt = now.Add(time.Hour * time.Duration(n))
+	case "days":
+		expireAt = now.Add(24 * time.Hour * time.Duration(n))
+	case "weeks":
+		expireAt = now.Add(7 * 24 * time.Hour * time.Duration(n))
+	case "months":
+		expireAt = now.AddDate(0, n, 0)
+	}
+	return int(expireAt.Unix()), nil
+}
+
+// formatExpiresAt renders an API expired_at for the expires_at attribute.
+// ipListNoExpiry is rendered as an empty string.
+func formatExpiresAt(expiredAt int) string {
+	if expiredAt == ipListNoExpiry {
+		return ""
+	}
+	return time.Unix(int64(expiredAt), 0).UTC().Format(time.RFC3339)
+}
+
+// parseExpiresAt is the inverse of formatExpiresAt.
+func parseExpiresAt(s string) (int, error) {
+	if s == "" {
+		return ipListNoExpiry, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid expires_at %q: %w", s, err)
+	}
+	return int(t.Unix()), nil
+}
+
+// isExpired reports whether expires_at lies in the past relative to now.
+func isExpired(expiresAt string, now time.Time) bool {
+	expiredAt, err := parseExpiresAt(expiresAt)
+	if err != nil || expiredAt == ipListNoExpiry {
+		return false
+	}
+	return int64(expiredAt) <= now.Unix()
+}
+
+// resolveIPListExpiry returns the expired_at to send to the API on Create/Update.
+// The stored expires_at is reused unless the plan marked it for recomputation
+// (see ipListExpiryCustomizeDiff) or the expiry arguments themselves changed.
+func resolveIPListExpiry(d *schema.ResourceData, now time.Time) (int, diag.Diagnostics) {
+	if !d.IsNewResource() && !d.HasChanges("time", "time_format", "expires_at") {
+		if expiredAt, err := parseExpiresAt(d.Get("expires_at").(string)); err == nil {
+			return expiredAt, nil
+		}
+	}
+	expiredAt, err := parseExpireTime(d.Get("time_format").(string), d.Get("time").(string), now)
+	if err != nil {
+		return 0, diag.FromErr(err)
+	}
+	return expiredAt, nil
+}
+
+// ipListExpiryCustomizeDiff plans expires_at according to the renew policy:
+//   - new resources and time/time_format changes compute a fresh expiry;
+//   - on_change recomputes when reason or application change;
+//   - always recomputes on every plan;
+//   - expired entries are re-added (fresh expiry) unless renew = never.
+//
+// Relative formats and Forever are only known after apply; RFC3339 is planned
+// as a concrete value. An edit of expired entries under never is an error.
+func ipListExpiryCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	renew := d.Get("renew").(string)
+	recompute := d.Id() == "" || d.HasChange("time") || d.HasChange("time_format")
+
+	switch renew {
+	case ipListRenewAlways:
+		recompute = true
+	case ipListRenewOnChange:
+		recompute = recompute || d.HasChange("reason") || d.HasChange("application")
+	}
+
+	if !recompute && d.Get("expired").(bool) {
+		if renew != ipListRenewNever {
+			recompute = true
+		} else if changed := ipListChangedKeys(d); len(changed) > 0 {
+			// Update re-adds the entries with the stored expiry, which is in
+			// the past: the Cloud would drop them again right away.
+			return fmt.Errorf("the entries expired at %s and renew = %q keeps that expiry, so changing %s would re-add them already expired. "+
+				"Change time or time_format to set a new expiry, or set renew = %q",
+				d.Get("expires_at").(string), ipListRenewNever, strings.Join(changed, ", "), ipListRenewOnChange)
+		}
+	}
+	if !recompute {
+		return nil
+	}
+
+	if err := d.SetNew("expired", false); err != nil {
+		return err
+	}
+
+	timeFormat := strings.ToLower(d.Get("time_format").(string))
+	if timeFormat == "rfc3339" && d.NewValueKnown("time") {
+		expiredAt, err := parseExpireTime(timeFormat, d.Get("time").(string), time.Now())
+		if err == nil {
+			return d.SetNew("expires_at", formatExpiresAt(expiredAt))
+		}
+	}
+	return d.SetNewComputed("expires_at")
+}
+
+// ipListEntryArgs are the arguments of wallarm_allowlist/denylist/graylist
+// and wallarm_ip_list_entry whose change makes Update re-add the entries.
+var ipListEntryArgs = []string{"application", "asn", "country", "datacenter", "ip_range", "proxy_type", "reason"}
+
+// ipListChangedKeys returns the ipListEntryArgs the plan changes.
+func ipListChangedKeys(d *schema.ResourceDiff) []string {
+	var keys []string
+	for _, k := range d.GetChangedKeysPrefix("") {
+		k, _, _ = strings.Cut(k, ".")
+		if slices.Contains(ipListEntryArgs, k) && !slices.Contains(keys, k) {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// ipListExpiryWarning returns a warning diagnostic when the entries expire
+// within IPListExpiryWarningWindow, or nil.
+func ipListExpiryWarning(id, expiresAt, renew string, now time.Time) diag.Diagnostics {
+	expiredAt, err := parseExpiresAt(expiresAt)
+	if err != nil || expiredAt == ipListNoExpiry {
+		return nil
+	}
+	left := time.Unix(int64(expiredAt), 0).Sub(now)
+	if left <= 0 || left > IPListExpiryWarningWindow {
+		return nil
+	}
+
+	detail := "The entries will be removed from the list by the Wallarm Cloud."
+	if renew != ipListRenewNever {
+		detail += " The next apply after expiry re-adds them with a fresh expiry."
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("IP list %s expires in %s (at %s)", id, left.Round(time.Minute), expiresAt),
+		Detail:   detail,
+	}}
+}
@@ -0,0 +1,199 @@
+package wallarm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/wallarm/wallarm-go"
+)
+
+func TestParseExpireTime(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		format string
+		value  string
+		want   time.Time
+	}{
+		{"Minutes", "30", now.Add(30 * time.Minute)},
+		{"Hours", "5", now.Add(5 * time.Hour)},
+		{"Days", "7", now.Add(7 * 24 * time.Hour)},
+		{"Weeks", "2", now.Add(14 * 24 * time.Hour)},
+		{"Months", "1", time.Date(2026, 2, 15, 12, 0, 0, 0, time.UTC)},
+		{"RFC3339", "2026-06-01T00:00:00+00:00", time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseExpireTime(tt.format, tt.value, now)
+		if err != nil {
+			t.Fatalf("%s/%s: unexpected error: %v", tt.format, tt.value, err)
+		}
+		if int64(got) != tt.want.Unix() {
+			t.Errorf("%s/%s = %d, want %d", tt.format, tt.value, got, tt.want.Unix())
+		}
+	}
+}
+
+func TestParseExpireTime_Forever(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		format, value string
+		want          time.Time
+	}{
+		{"Forever", "", now.AddDate(ipListForeverYears, 0, 0)},
+		{"forever", "10", now.AddDate(ipListForeverYears, 0, 0)},
+		{"Days", "0", now.AddDate(ipListForeverYears, 0, 0)},
+	} {
+		got, err := parseExpireTime(tc.format, tc.value, now)
+		if err != nil {
+			t.Fatalf("%s/%s: unexpected error: %v", tc.format, tc.value, err)
+		}
+		if int64(got) != tc.want.Unix() {
+			t.Errorf("%s/%s = %d, want %d", tc.format, tc.value, got, tc.want.Unix())
+		}
+	}
+}
+
+func TestParseExpireTime_Errors(t *testing.T) {
+	now := time.Now()
+	for _, tc := range [][2]string{{"Days", "seven"}, {"RFC3339", "tomorrow"}, {"Years", "1"}} {
+		if _, err := parseExpireTime(tc[0], tc[1], now); err == nil {
+			t.Errorf("%v: expected error", tc)
+		}
+	}
+}
+
+func TestExpiresAtRoundTrip(t *testing.T) {
+	if got := formatExpiresAt(ipListNoExpiry); got != "" {
+		t.Errorf("formatExpiresAt(no expiry) = %q, want empty", got)
+	}
+	ts := 1804809600
+	s := formatExpiresAt(ts)
+	if s != "2027-03-12T00:00:00Z" {
+		t.Errorf("formatExpiresAt(%d) = %q", ts, s)
+	}
+	back, err := parseExpiresAt(s)
+	if err != nil || back != ts {
+		t.Errorf("parseExpiresAt(%q) = %d, %v; want %d", s, back, err, ts)
+	}
+}
+
+func TestIsExpired(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	if isExpired("", now) {
+		t.Error("no expiry must never be expired")
+	}
+	if !isExpired("2026-01-15T11:59:59Z", now) {
+		t.Error("past expiry must be expired")
+	}
+	if isExpired("2026-01-15T12:00:01Z", now) {
+		t.Error("future expiry must not be expired")
+	}
+}
+
+func TestEarliestExpiredAt(t *testing.T) {
+	if got := earliestExpiredAt(nil); got != ipListNoExpiry {
+		t.Errorf("empty = %d, want no expiry", got)
+	}
+	entries := []IPCacheEntry{{ExpiredAt: 0}, {ExpiredAt: 300}, {ExpiredAt: 200}}
+	if got := earliestExpiredAt(entries); got != 200 {
+		t.Errorf("got %d, want 200", got)
+	}
+}
+
+func TestIPListExpiryWarning(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	if d := ipListExpiryWarning("id", "", ipListRenewNever, now); d != nil {
+		t.Error("no expiry must not warn")
+	}
+	if d := ipListExpiryWarning("id", "2026-02-15T12:00:00Z", ipListRenewNever, now); d != nil {
+		t.Error("expiry outside the window must not warn")
+	}
+	if d := ipListExpiryWarning("id", "2026-01-15T11:00:00Z", ipListRenewNever, now); d != nil {
+		t.Error("already expired entries must not warn")
+	}
+	d := ipListExpiryWarning("id", "2026-01-15T14:00:00Z", ipListRenewOnChange, now)
+	if len(d) != 1 {
+		t.Fatalf("expected one warning, got %v", d)
+	}
+	if d[0].Summary != "IP list id expires in 2h0m0s (at 2026-01-15T14:00:00Z)" {
+		t.Errorf("unexpected summary %q", d[0].Summary)
+	}
+}
+
+func TestIPListExpiryCustomizeDiff_ReasonChange(t *testing.T) {
+	res := resourceWallarmIPList(wallarm.DenylistType)
+	state := &terraform.InstanceState{ID: "1/denylist", Attributes: map[string]string{
+		"id":            "1/denylist",
+		"ip_range.#":    "1",
+		"ip_range.0":    "1.2.3.4",
+		"time_format":   "Days",
+		"time":          "7",
+		"reason":        "old",
+		"renew":         ipListRenewNever,
+		"expires_at":    "2026-01-22T12:00:00Z",
+		"expired":       "false",
+		"application.#": "0",
+	}}
+
+	for renew, recompute := range map[string]bool{"": false, ipListRenewNever: false, ipListRenewOnChange: true} {
+		raw := map[string]any{"ip_range": []any{"1.2.3.4"}, "time_format": "Days", "time": "7", "reason": "new"}
+		if renew != "" {
+			raw["renew"] = renew
+		}
+		diff, err := res.Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), nil)
+		if err != nil {
+			t.Fatalf("renew %q: %v", renew, err)
+		}
+		_, got := diff.Attributes["expires_at"]
+		if got != recompute {
+			t.Errorf("renew %q: expires_at in diff = %t, want %t", renew, got, recompute)
+		}
+	}
+}
+
+func TestIPListExpiryCustomizeDiff_ExpiredNever(t *testing.T) {
+	res := resourceWallarmIPList(wallarm.DenylistType)
+	state := &terraform.InstanceState{ID: "1/denylist", Attributes: map[string]string{
+		"id":            "1/denylist",
+		"ip_range.#":    "1",
+		"ip_range.0":    "1.2.3.4",
+		"time_format":   "Days",
+		"time":          "7",
+		"reason":        "old",
+		"renew":         ipListRenewNever,
+		"expires_at":    "2026-01-22T12:00:00Z",
+		"expired":       "true",
+		"application.#": "0",
+	}}
+	diff := func(raw map[string]any) (*terraform.InstanceDiff, error) {
+		raw["ip_range"] = []any{"1.2.3.4"}
+		raw["renew"] = ipListRenewNever
+		return res.Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), nil)
+	}
+
+	// Without changes the expired entries stay in state.
+	d, err := diff(map[string]any{"time_format": "Days", "time": "7", "reason": "old"})
+	if err != nil {
+		t.Fatalf("unchanged: %v", err)
+	}
+	if _, ok := d.Attributes["expires_at"]; ok {
+		t.Error("unchanged: expires_at must not be recomputed")
+	}
+	// An edit would re-add them already expired.
+	_, err = diff(map[string]any{"time_format": "Days", "time": "7", "reason": "new"})
+	if err == nil || !strings.Contains(err.Error(), "changing reason") {
+		t.Errorf("reason change: error %v, want a re-add already expired error", err)
+	}
+	// A new time sets a fresh expiry.
+	d, err = diff(map[string]any{"time_format": "Days", "time": "8", "reason": "new"})
+	if err != nil {
+		t.Fatalf("time change: %v", err)
+	}
+	if attr := d.Attributes["expires_at"]; attr == nil || !attr.NewComputed {
+		t.Errorf("time change: expires_at = %+v, want recomputed", attr)
+	}
+}
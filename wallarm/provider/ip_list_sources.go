@@ -0,0 +1,84 @@
+package wallarm
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// API rule types of IP list groups beyond subnet.
+const (
+	ruleTypeLocation   = "location"
+	ruleTypeDatacenter = "datacenter"
+	ruleTypeProxyType  = "proxy_type"
+	ruleTypeASN        = "asn"
+)
+
+// ipListSourceCatalogVersion identifies the snapshot of the Cloud's source
+// classifiers in ipListSourceCatalog. Bump it together with the table when the
+// Cloud adds or retires values, and note the change in CHANGELOG.md.
+const ipListSourceCatalogVersion = "2026-10"
+
+// ipListSourceCatalog lists the values the access-rules API accepts for each
+// classifier rule type. The datacenter and proxy type codes are the ones the
+// resources validated inline before the catalog existed; the API has no
+// endpoint listing them. ASNs are free-form numbers and have no entry.
+var ipListSourceCatalog = map[string][]string{
+	ruleTypeDatacenter: {"alibaba", "aws", "azure", "docean", "gce", "hetzner", "huawei", "ibm", "linode", "oracle", "ovh", "plusserver", "rackspace", "tencent"},
+	ruleTypeProxyType:  {"DCH", "MIP", "PUB", "WEB", "SES", "TOR", "VPN"},
+}
+
+// ipListSourceValues returns the accepted values for a classifier rule type.
+func ipListSourceValues(ruleType string) []string {
+	return ipListSourceCatalog[ruleType]
+}
+
+// validateIPListSource returns a SchemaValidateFunc checking a value against
+// ipListSourceCatalog for the given rule type.
+func validateIPListSource(ruleType string) schema.SchemaValidateFunc {
+	return func(v any, k string) ([]string, []error) {
+		if err := checkIPListSource(ruleType, v.(string)); err != nil {
+			return nil, []error{fmt.Errorf("%s: %w", k, err)}
+		}
+		return nil, nil
+	}
+}
+
+func checkIPListSource(ruleType, value string) error {
+	allowed := ipListSourceValues(ruleType)
+	if slices.Contains(allowed, value) {
+		return nil
+	}
+	return fmt.Errorf("%q is not a valid %s, expected one of %s (catalog %s)",
+		value, ruleType, strings.Join(allowed, ", "), ipListSourceCatalogVersion)
+}
+
+// validateASN is a SchemaValidateFunc for ASN values.
+func validateASN(v any, k string) ([]string, []error) {
+	if _, err := normalizeASN(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+// suppressASNDiff hides the difference between "AS13335" in config and the
+// "13335" the API returns to Read and import.
+func suppressASNDiff(_, old, new string, _ *schema.ResourceData) bool {
+	o, errOld := normalizeASN(old)
+	n, errNew := normalizeASN(new)
+	return errOld == nil && errNew == nil && o == n
+}
+
+// normalizeASN accepts "13335", "AS13335" or "as13335" and returns the bare
+// number the API stores.
+func normalizeASN(value string) (string, error) {
+	trimmed := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "AS")
+	n, err := strconv.ParseUint(trimmed, 10, 32)
+	if err != nil || n == 0 {
+		return "", fmt.Errorf("%q is not a valid ASN, expected a number between 1 and 4294967295", value)
+	}
+	return strconv.FormatUint(n, 10), nil
+}
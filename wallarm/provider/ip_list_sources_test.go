@@ -0,0 +1,107 @@
+package wallarm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	wallarm "github.com/wallarm/wallarm-go"
+)
+
+func TestNormalizeASN(t *testing.T) {
+	for in, want := range map[string]string{"13335": "13335", "AS13335": "13335", "as15169": "15169", " AS1 ": "1", "4294967295": "4294967295"} {
+		got, err := normalizeASN(in)
+		if err != nil || got != want {
+			t.Errorf("normalizeASN(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "AS", "0", "ASN13335", "-1", "4294967296", "13335.5"} {
+		if _, err := normalizeASN(in); err == nil {
+			t.Errorf("normalizeASN(%q): expected error", in)
+		}
+	}
+}
+
+func TestCheckIPListSource(t *testing.T) {
+	if err := checkIPListSource(ruleTypeDatacenter, "aws"); err != nil {
+		t.Errorf("aws: unexpected error %v", err)
+	}
+	if err := checkIPListSource(ruleTypeProxyType, "TOR"); err != nil {
+		t.Errorf("TOR: unexpected error %v", err)
+	}
+	err := checkIPListSource(ruleTypeProxyType, "tor")
+	if err == nil || !strings.Contains(err.Error(), ipListSourceCatalogVersion) {
+		t.Errorf("expected error naming the catalog version, got %v", err)
+	}
+}
+
+func TestIPListConflicts(t *testing.T) {
+	got := ipListConflicts("asn")
+	if len(got) != len(ipListSourceFields)-1 {
+		t.Fatalf("got %v", got)
+	}
+	for _, f := range got {
+		if f == "asn" {
+			t.Error("field must not conflict with itself")
+		}
+	}
+}
+
+func TestBuildRulesFromSchema_ASN(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceWallarmIPList(wallarm.DenylistType).Schema, map[string]any{
+		"asn":         []any{"AS13335", "15169"},
+		"time_format": "Forever",
+	})
+
+	rules, diags := buildRulesFromSchema(d)
+	if diags != nil {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	if len(rules) != 1 || rules[0].RulesType != ruleTypeASN {
+		t.Fatalf("got %+v", rules)
+	}
+	if strings.Join(rules[0].Values, ",") != "13335,15169" {
+		t.Errorf("values: got %v", rules[0].Values)
+	}
+	if got := ipListConfigValues(d); strings.Join(got, ",") != "13335,15169" {
+		t.Errorf("config values: got %v", got)
+	}
+	if got := ipListRuleTypes(rules); got != "asn" {
+		t.Errorf("rule types: got %q", got)
+	}
+}
+
+// TestASNDiffSuppress plans "AS13335" in config against the "13335" import
+// and Read store.
+func TestASNDiffSuppress(t *testing.T) {
+	ctx := context.Background()
+	list := &terraform.InstanceState{ID: "1/denylist", Attributes: map[string]string{
+		"id": "1/denylist", "asn.#": "1", "asn.0": "13335", "time_format": "Forever",
+		"reason": "Terraform managed IP list", "renew": ipListRenewNever,
+	}}
+	diff, err := resourceWallarmIPList(wallarm.DenylistType).Diff(ctx, list, terraform.NewResourceConfigRaw(map[string]any{
+		"asn": []any{"AS13335"}, "time_format": "Forever",
+	}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := diff.Attributes["asn.0"]; ok {
+		t.Errorf("asn diff after import: %v", diff.Attributes["asn.0"])
+	}
+
+	entry := &terraform.InstanceState{ID: "1/denylist/13335", Attributes: map[string]string{
+		"id": "1/denylist/13335", "list_type": "denylist", "rule_type": "asn", "value": "13335",
+		"time_format": "Forever", "reason": "Terraform managed IP list", "renew": ipListRenewNever,
+	}}
+	diff, err = resourceWallarmIPListEntry().Diff(ctx, entry, terraform.NewResourceConfigRaw(map[string]any{
+		"list_type": "denylist", "rule_type": "asn", "value": "AS13335", "time_format": "Forever",
+	}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.RequiresNew() {
+		t.Errorf("entry replaced after import: %v", diff.Attributes)
+	}
+}
@@ -99,7 +99,8 @@ func isSensitiveHTTPHeader(key string) bool {
 	lower := strings.ToLower(key)
 	return strings.Contains(lower, "token") ||
 		strings.Contains(lower, "secret") ||
-		strings.Contains(lower, "authorization")
+		strings.Contains(lower, "authorization") ||
+		strings.Contains(lower, "cookie")
 }
 
 func maskHTTPValue(val string) string {
@@ -9,6 +9,7 @@ import (
 	"log"
 	"net/http"
 	"regexp"
+	"time"
 
 	"github.com/wallarm/terraform-provider-wallarm/version"
 	"github.com/wallarm/wallarm-go"
@@ -16,7 +17,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
-	cleanhttp "github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
@@ -30,8 +30,62 @@ func Provider() *schema.Provider {
 				Type:         schema.TypeString,
 				Optional:     true,
 				DefaultFunc:  schema.EnvDefaultFunc("WALLARM_API_HOST", "https://api.wallarm.com"),
-				Description:  "The API host address of the Wallarm Cloud for operations",
-				ValidateFunc: validation.IsURLWithHTTPS,
+				Description:  "The API host address of the Wallarm Cloud for operations. Plain HTTP requires allow_http.",
+				ValidateFunc: validation.IsURLWithScheme([]string{"https", "http"}),
+			},
+			"allow_http": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("WALLARM_API_ALLOW_HTTP", false),
+				Description: "Allow a plain-HTTP api_host, e.g. a local test stand-in. The API token is sent unencrypted.",
+			},
+			"proxy_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("WALLARM_API_PROXY_URL", nil),
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https", "socks5"}),
+				Description:  "Proxy for API requests, overriding HTTPS_PROXY/NO_PROXY.",
+			},
+			"ca_bundle": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("WALLARM_API_CA_BUNDLE", nil),
+				Description: "PEM CA certificates, inline or as a file path, trusted for the API and proxy in addition to the system pool.",
+			},
+			"client_cert": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("WALLARM_API_CLIENT_CERT", nil),
+				RequiredWith: []string{"client_key"},
+				Description:  "PEM client certificate for mutual TLS, inline or as a file path.",
+			},
+			"client_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				DefaultFunc:  schema.EnvDefaultFunc("WALLARM_API_CLIENT_KEY", nil),
+				RequiredWith: []string{"client_cert"},
+				Description:  "PEM private key of client_cert, inline or as a file path.",
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("WALLARM_API_INSECURE_SKIP_VERIFY", false),
+				Description: "Skip TLS certificate verification of the API and proxy. For test setups only.",
+			},
+			"connect_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("WALLARM_API_CONNECT_TIMEOUT", 30),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Seconds to wait for a TCP connection to the API or proxy. Defaults to 30.",
+			},
+			"request_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("WALLARM_API_REQUEST_TIMEOUT", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Seconds one API request attempt may take, including reading the response. 0 (default) means no limit.",
 			},
 			"api_uuid": {
 				Deprecated:   "This field is deprecated. Please use the api_token field instead.",
@@ -60,6 +114,22 @@ func Provider() *schema.Provider {
 				Sensitive:     true,
 				ConflictsWith: []string{"api_secret", "api_uuid"},
 			},
+			"api_token_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("WALLARM_API_TOKEN_FILE", nil),
+				Description:   "Path to a file holding the API token. The file is re-read when the API rejects the token, so it can be rotated during a run. Takes precedence over api_token.",
+				ConflictsWith: []string{"api_secret", "api_uuid"},
+			},
+			"api_token_command": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Description: "Command and arguments of a credential helper printing {\"token\": \"...\", \"expires_at\": \"<RFC3339>\"} on stdout. " +
+					"It is run on first use, again before expires_at and after the API rejects the token. Takes precedence over api_token_file and api_token.",
+				ConflictsWith: []string{"api_secret", "api_uuid"},
+			},
 			"client_id": {
 				Type:        schema.TypeInt,
 				Optional:    true,
@@ -85,6 +155,21 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("WALLARM_API_MAX_BACKOFF", 5),
 				Description: "Maximum backoff period in seconds after failed API calls",
 			},
+			"requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("WALLARM_API_REQUESTS_PER_SECOND", 0.0),
+				ValidateFunc: validation.FloatAtLeast(0),
+				Description: "Maximum average number of API requests per second, with bursts of up to one second's worth. " +
+					"0 (default) means unlimited.",
+			},
+			"max_in_flight_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("WALLARM_API_MAX_IN_FLIGHT_REQUESTS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of concurrent API requests. 0 (default) means unlimited.",
+			},
 			"api_client_logging": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -99,6 +184,29 @@ func Provider() *schema.Provider {
 					"When enabled, the first rule read triggers a bulk fetch of all hints for the client, " +
 					"and subsequent reads are served from an in-memory cache. Defaults to true.",
 			},
+			"hint_prefetch_concurrency": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("WALLARM_HINT_PREFETCH_CONCURRENCY", 4),
+				ValidateFunc: validation.IntBetween(1, 32),
+				Description: "Maximum number of hint pages fetched in parallel by hint_prefetch once the hint count is known. " +
+					"1 fetches one page at a time. Defaults to 4.",
+			},
+			"cache_dir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("WALLARM_CACHE_DIR", nil),
+				Description: "Directory to persist the hint_prefetch cache in across runs, one snapshot per client. " +
+					"The next run starts from the snapshot and only fetches hints updated since, falling back to a full load " +
+					"when the snapshot is expired or does not match the API. Disabled if unset.",
+			},
+			"cache_ttl": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("WALLARM_CACHE_TTL", 86400),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Seconds after a full load its cache_dir snapshot stops being used. Defaults to 86400 (24 hours).",
+			},
 			"require_explicit_client_id": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -106,6 +214,28 @@ func Provider() *schema.Provider {
 				Description: "When true, every resource must set client_id explicitly. " +
 					"Prevents accidental cross-tenant operations for Global Administrator tokens managing multiple tenants.",
 			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("WALLARM_READ_ONLY", false),
+				Description: "Refuse every create, update and delete before any request is sent, for drift-detection jobs " +
+					"that must never change the Wallarm Cloud. Reads, refresh, import and data sources keep working.",
+			},
+			"audit_log_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("WALLARM_AUDIT_LOG_PATH", nil),
+				Description: "File the provider appends one JSON line to per mutating API request: time, client ID, " +
+					"operation, object IDs, request body with secrets masked, response status and duration. Disabled if unset.",
+			},
+			"on_conflict": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("WALLARM_ON_CONFLICT", onConflictError),
+				ValidateFunc: validation.StringInSlice(onConflictValues, false),
+				Description: "Default on_conflict of rule resources: what to do when a rule of the same type " +
+					"already exists on the action scope at create time. One of: error, adopt, replace. Defaults to error.",
+			},
 		},
 		ProviderMetaSchema: map[string]*schema.Schema{
 			"module_name": {
@@ -114,13 +244,16 @@ func Provider() *schema.Provider {
 			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"wallarm_actions":         dataSourceWallarmActions(),
-			"wallarm_node":            dataSourceWallarmNode(),
-			"wallarm_security_issues": dataSourceWallarmSecurityIssues(),
-			"wallarm_hits":            dataSourceWallarmHits(),
-			"wallarm_ip_lists":        dataSourceWallarmIPLists(),
-			"wallarm_applications":    dataSourceWallarmApplications(),
-			"wallarm_rules":           dataSourceWallarmRules(),
+			"wallarm_actions":           dataSourceWallarmActions(),
+			"wallarm_node":              dataSourceWallarmNode(),
+			"wallarm_security_issues":   dataSourceWallarmSecurityIssues(),
+			"wallarm_hits":              dataSourceWallarmHits(),
+			"wallarm_ip_lists":          dataSourceWallarmIPLists(),
+			"wallarm_applications":      dataSourceWallarmApplications(),
+			"wallarm_rules":             dataSourceWallarmRules(),
+			"wallarm_integration_check": dataSourceWallarmIntegrationCheck(),
+			"wallarm_integrations":      dataSourceWallarmIntegrations(),
+			"wallarm_triggers":          dataSourceWallarmTriggers(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"wallarm_action":                         resourceWallarmAction(),
@@ -138,6 +271,8 @@ func Provider() *schema.Provider {
 			"wallarm_denylist":                       resourceWallarmDenylist(),
 			"wallarm_allowlist":                      resourceWallarmAllowlist(),
 			"wallarm_graylist":                       resourceWallarmGraylist(),
+			"wallarm_ip_list_entry":                  resourceWallarmIPListEntry(),
+			"wallarm_integration":                    resourceWallarmIntegration(),
 			"wallarm_integration_email":              resourceWallarmEmail(),
 			"wallarm_integration_opsgenie":           resourceWallarmOpsGenie(),
 			"wallarm_integration_slack":              resourceWallarmSlack(),
@@ -150,6 +285,7 @@ func Provider() *schema.Provider {
 			"wallarm_integration_telegram":           resourceWallarmTelegram(),
 			"wallarm_integration_teams":              resourceWallarmTeams(),
 			"wallarm_trigger":                        resourceWallarmTrigger(),
+			"wallarm_notification_policy":            resourceWallarmNotificationPolicy(),
 			"wallarm_rule_api_abuse_mode":            resourceWallarmAPIAbuseMode(),
 			"wallarm_rule_binary_data":               resourceWallarmBinaryData(),
 			"wallarm_rule_enum":                      resourceWallarmEnum(),
@@ -185,44 +321,61 @@ func Provider() *schema.Provider {
 	return provider
 }
 
-func ProviderConfigure(_ context.Context, d *schema.ResourceData, p *schema.Provider) (any, diag.Diagnostics) {
+func ProviderConfigure(ctx context.Context, d *schema.ResourceData, p *schema.Provider) (any, diag.Diagnostics) {
 	retryOpt := wallarm.UsingRetryPolicy(d.Get("retries").(int), d.Get("min_backoff").(int), d.Get("max_backoff").(int))
 	options := []wallarm.Option{retryOpt}
 
-	c := cleanhttp.DefaultPooledClient()
+	c, err := newAPIHTTPClient(d)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	cassette, err := cassetteTransportFromEnv(c.Transport)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	c.Transport = cassette
 	if d.Get("api_client_logging").(bool) {
 		c.Transport = newLoggingTransport(c.Transport)
 	} else {
 		c.Transport = logging.NewSubsystemLoggingHTTPTransport("Wallarm", c.Transport)
 	}
-	options = append(options, wallarm.HTTPClient(c))
+	if v, ok := d.GetOk("audit_log_path"); ok {
+		audit, err := openAuditLog(v.(string))
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		c.Transport = newAuditTransport(c.Transport, audit)
+	}
+	throttle := newThrottleTransport(c.Transport, d.Get("requests_per_second").(float64), d.Get("max_in_flight_requests").(int))
+	c.Transport = throttle
+	registerRunStats(throttle.LogStats)
 
 	ua := p.UserAgent("terraform-provider-wallarm", version.ProviderVersion)
 
 	options = append(options, wallarm.UserAgent(ua))
 
-	authHeaders := make(http.Header)
 	config := Config{}
 
-	if v, ok := d.GetOk("api_token"); ok {
-		authHeaders.Add("X-WallarmAPI-Token", v.(string))
-	} else {
-		if v, ok := d.GetOk("api_uuid"); ok {
-			authHeaders.Add("X-WallarmAPI-UUID", v.(string))
-		} else {
-			return nil, diag.FromErr(fmt.Errorf("api_uuid is required when api_token is not set: %w", wallarm.ErrInvalidCredentials))
-		}
-		if v, ok := d.GetOk("api_secret"); ok {
-			authHeaders.Add("X-WallarmAPI-Secret", v.(string))
-		} else {
-			return nil, diag.FromErr(fmt.Errorf("api_secret is required when api_token is not set: %w", wallarm.ErrInvalidCredentials))
-		}
+	apiHost := "https://api.wallarm.com"
+	if v, ok := d.GetOk("api_host"); ok {
+		apiHost = v.(string)
+		options = append(options, wallarm.UsingBaseURL(apiHost))
+	}
+	if err := validateAPIHost(apiHost, d.Get("allow_http").(bool)); err != nil {
+		return nil, diag.FromErr(err)
 	}
 
-	if v, ok := d.GetOk("api_host"); ok {
-		options = append(options, wallarm.UsingBaseURL(v.(string)))
+	// Auth headers are set per request, so credentials from a file or a
+	// helper can change during a run.
+	source, err := tokenSourceFromConfig(d, apiHost)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	if _, err := source.authHeaders(ctx); err != nil {
+		return nil, diag.FromErr(err)
 	}
-	options = append(options, wallarm.Headers(authHeaders))
+	c.Transport = newAuthTransport(c.Transport, source)
+	options = append(options, wallarm.HTTPClient(c))
 	config.Options = options
 
 	client, err := config.Client()
@@ -242,10 +395,24 @@ func ProviderConfigure(_ context.Context, d *schema.ResourceData, p *schema.Prov
 		defaultClientID = u.Body.Clientid
 	}
 
+	raw := newRawAPI(c, apiHost, http.Header{}, ua)
+	if d.Get("read_only").(bool) {
+		log.Printf("[INFO] Wallarm provider is read-only — creates, updates and deletes will fail")
+		client = newReadOnlyClient(client)
+		raw.readOnly = true
+	}
+
 	// Wrap with caching layer if hint_prefetch is enabled (default: true)
 	if d.Get("hint_prefetch").(bool) {
 		log.Printf("[INFO] Wallarm hint prefetch enabled — rule reads will use bulk cache")
-		client = NewCachedClient(client)
+		cached := NewCachedClient(client)
+		registerRunStats(cached.LogHintCacheStats)
+		cached.SetPrefetchConcurrency(rawHintCounter(raw), d.Get("hint_prefetch_concurrency").(int))
+		if dir, ok := d.GetOk("cache_dir"); ok {
+			ttl := time.Duration(d.Get("cache_ttl").(int)) * time.Second
+			cached.SetHintSnapshot(NewHintSnapshotStore(dir.(string), ttl, apiHost, rawHintDelta(raw)))
+		}
+		client = cached
 	}
 
 	return &ProviderMeta{
@@ -254,5 +421,7 @@ func ProviderConfigure(_ context.Context, d *schema.ResourceData, p *schema.Prov
 		RequireExplicitClientID: d.Get("require_explicit_client_id").(bool),
 		IPListCache:             NewIPListCache(),
 		CredentialStuffingCache: NewCredentialStuffingCache(),
+		RawAPI:                  raw,
+		OnConflict:              d.Get("on_conflict").(string),
 	}, nil
 }
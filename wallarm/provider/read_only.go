@@ -0,0 +1,238 @@
+package wallarm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/wallarm/wallarm-go"
+)
+
+// errReadOnly is wrapped by every write refused because of read_only.
+var errReadOnly = errors.New("the provider is configured with read_only = true and does not modify the Wallarm Cloud")
+
+func readOnlyError(op string) error {
+	return fmt.Errorf("%s refused before sending any request: %w", op, errReadOnly)
+}
+
+// readOnlyClient is a wallarm.API that fails every create, update and delete
+// without sending a request, for drift-detection jobs that must never change
+// production. Reads pass through. It sits below CachedClient, so refused
+// writes never reach the hint cache either.
+type readOnlyClient struct {
+	wallarm.API
+}
+
+func newReadOnlyClient(client wallarm.API) wallarm.API {
+	return &readOnlyClient{API: client}
+}
+
+func (c *readOnlyClient) HintCreate(*wallarm.ActionCreate) (*wallarm.ActionCreateResp, error) {
+	return nil, readOnlyError("HintCreate")
+}
+
+func (c *readOnlyClient) HintUpdateV3(int, *wallarm.HintUpdateV3Params) (*wallarm.ActionCreateResp, error) {
+	return nil, readOnlyError("HintUpdateV3")
+}
+
+func (c *readOnlyClient) HintDelete(*wallarm.HintDelete) (*wallarm.HintDeleteResp, error) {
+	return nil, readOnlyError("HintDelete")
+}
+
+func (c *readOnlyClient) AppCreate(*wallarm.AppCreate) error {
+	return readOnlyError("AppCreate")
+}
+
+func (c *readOnlyClient) AppUpdate(*wallarm.AppUpdate) error {
+	return readOnlyError("AppUpdate")
+}
+
+func (c *readOnlyClient) AppDelete(*wallarm.AppDelete) error {
+	return readOnlyError("AppDelete")
+}
+
+func (c *readOnlyClient) IPListCreate(int, wallarm.AccessRuleCreateRequest) error {
+	return readOnlyError("IPListCreate")
+}
+
+func (c *readOnlyClient) IPListDelete(int, []wallarm.AccessRuleDeleteEntry) error {
+	return readOnlyError("IPListDelete")
+}
+
+func (c *readOnlyClient) AllowlistCreate(int, wallarm.AccessRuleCreateRequest) error {
+	return readOnlyError("AllowlistCreate")
+}
+
+func (c *readOnlyClient) AllowlistDelete(int, []wallarm.AccessRuleDeleteEntry) error {
+	return readOnlyError("AllowlistDelete")
+}
+
+func (c *readOnlyClient) DenylistCreate(int, wallarm.AccessRuleCreateRequest) error {
+	return readOnlyError("DenylistCreate")
+}
+
+func (c *readOnlyClient) DenylistDelete(int, []wallarm.AccessRuleDeleteEntry) error {
+	return readOnlyError("DenylistDelete")
+}
+
+func (c *readOnlyClient) GraylistCreate(int, wallarm.AccessRuleCreateRequest) error {
+	return readOnlyError("GraylistCreate")
+}
+
+func (c *readOnlyClient) GraylistDelete(int, []wallarm.AccessRuleDeleteEntry) error {
+	return readOnlyError("GraylistDelete")
+}
+
+func (c *readOnlyClient) ClientCreate(*wallarm.ClientCreate) (*wallarm.SingleClientInfo, error) {
+	return nil, readOnlyError("ClientCreate")
+}
+
+func (c *readOnlyClient) ClientUpdate(*wallarm.ClientUpdate) (*wallarm.ClientInfo, error) {
+	return nil, readOnlyError("ClientUpdate")
+}
+
+func (c *readOnlyClient) ClientDelete(*wallarm.ClientDelete) (*wallarm.ClientDeleteResponse, error) {
+	return nil, readOnlyError("ClientDelete")
+}
+
+func (c *readOnlyClient) IntegrationCreate(*wallarm.IntegrationCreate) (*wallarm.IntegrationCreateResp, error) {
+	return nil, readOnlyError("IntegrationCreate")
+}
+
+func (c *readOnlyClient) IntegrationUpdate(*wallarm.IntegrationCreate, int) (*wallarm.IntegrationCreateResp, error) {
+	return nil, readOnlyError("IntegrationUpdate")
+}
+
+func (c *readOnlyClient) IntegrationPartialUpdate(int, map[string]any) (*wallarm.IntegrationCreateResp, error) {
+	return nil, readOnlyError("IntegrationPartialUpdate")
+}
+
+func (c *readOnlyClient) IntegrationDelete(int) error {
+	return readOnlyError("IntegrationDelete")
+}
+
+func (c *readOnlyClient) IntegrationWithAPICreate(*wallarm.IntegrationWithAPICreate) (*wallarm.IntegrationCreateResp, error) {
+	return nil, readOnlyError("IntegrationWithAPICreate")
+}
+
+func (c *readOnlyClient) IntegrationWithAPIUpdate(*wallarm.IntegrationWithAPICreate, int) (*wallarm.IntegrationCreateResp, error) {
+	return nil, readOnlyError("IntegrationWithAPIUpdate")
+}
+
+func (c *readOnlyClient) EmailIntegrationCreate(*wallarm.EmailIntegrationCreate) (*wallarm.IntegrationCreateResp, error) {
+	return nil, readOnlyError("EmailIntegrationCreate")
+}
+
+func (c *readOnlyClient) EmailIntegrationUpdate(*wallarm.EmailIntegrationCreate, int) (*wallarm.IntegrationCreateResp, error) {
+	return nil, readOnlyError("EmailIntegrationUpdate")
+}
+
+func (c *readOnlyClient) TelegramIntegrationCreate(*wallarm.TelegramIntegrationCreate) (*wallarm.IntegrationCreateResp, error) {
+	return nil, readOnlyError("TelegramIntegrationCreate")
+}
+
+func (c *readOnlyClient) TelegramIntegrationUpdate(*wallarm.TelegramIntegrationCreate, int) (*wallarm.IntegrationCreateResp, error) {
+	return nil, readOnlyError("TelegramIntegrationUpdate")
+}
+
+func (c *readOnlyClient) NodeCreate(*wallarm.NodeCreate) (*wallarm.NodeCreateResp, error) {
+	return nil, readOnlyError("NodeCreate")
+}
+
+func (c *readOnlyClient) NodeDelete(int) error {
+	return readOnlyError("NodeDelete")
+}
+
+func (c *readOnlyClient) TriggerCreate(*wallarm.TriggerCreate, int) (*wallarm.TriggerCreateResp, error) {
+	return nil, readOnlyError("TriggerCreate")
+}
+
+func (c *readOnlyClient) TriggerUpdate(*wallarm.TriggerCreate, int, int) (*wallarm.TriggerCreateResp, error) {
+	return nil, readOnlyError("TriggerUpdate")
+}
+
+func (c *readOnlyClient) TriggerDelete(int, int) error {
+	return readOnlyError("TriggerDelete")
+}
+
+func (c *readOnlyClient) UserCreate(*wallarm.UserCreate) (*wallarm.UserCreateResponse, error) {
+	return nil, readOnlyError("UserCreate")
+}
+
+func (c *readOnlyClient) UserUpdate(*wallarm.UserUpdate) error {
+	return readOnlyError("UserUpdate")
+}
+
+func (c *readOnlyClient) UserDelete(*wallarm.UserDelete) error {
+	return readOnlyError("UserDelete")
+}
+
+func (c *readOnlyClient) WallarmModeUpdate(*wallarm.WallarmModeParams, int) (*wallarm.WallarmModeResponse, error) {
+	return nil, readOnlyError("WallarmModeUpdate")
+}
+
+func (c *readOnlyClient) OverlimitResSettingsUpdate(*wallarm.OverlimitResSettingsParams, int) (*wallarm.OverlimitResSettingsResponse, error) {
+	return nil, readOnlyError("OverlimitResSettingsUpdate")
+}
+
+func (c *readOnlyClient) RulesSettingsUpdate(*wallarm.RuleSettingsParams, int) (*wallarm.RulesSettingsResponse, error) {
+	return nil, readOnlyError("RulesSettingsUpdate")
+}
+
+func (c *readOnlyClient) APISpecCreate(*wallarm.APISpecCreate) (wallarm.APISpecCreateResp, error) {
+	return wallarm.APISpecCreateResp{}, readOnlyError("APISpecCreate")
+}
+
+func (c *readOnlyClient) APISpecUpdate(int, int, *wallarm.APISpecUpdate) (wallarm.APISpecCreateResp, error) {
+	return wallarm.APISpecCreateResp{}, readOnlyError("APISpecUpdate")
+}
+
+func (c *readOnlyClient) APISpecDelete(int, int) error {
+	return readOnlyError("APISpecDelete")
+}
+
+func (c *readOnlyClient) APISpecPolicyPut(int, int, *wallarm.APISpecPolicy) (wallarm.APISpecPolicyResp, error) {
+	return wallarm.APISpecPolicyResp{}, readOnlyError("APISpecPolicyPut")
+}
+
+func (c *readOnlyClient) APIDiscoveryConfigUpdate(int, *wallarm.APIDiscoveryConfig) error {
+	return readOnlyError("APIDiscoveryConfigUpdate")
+}
+
+// apiReadPOSTPaths are the endpoints that take a read's filter as a POST body.
+var apiReadPOSTPaths = map[string]bool{
+	"/v1/user":                         true,
+	"/v1/objects/hint":                 true,
+	"/v1/objects/hint/count":           true,
+	"/v1/objects/action":               true,
+	"/v1/objects/action/by_hit":        true,
+	"/v1/objects/pool":                 true,
+	"/v1/objects/user":                 true,
+	"/v1/objects/node":                 true,
+	"/v1/objects/attack":               true,
+	"/v1/objects/attack/count":         true,
+	"/v1/objects/attack/ip":            true,
+	"/v1/objects/hit":                  true,
+	"/v1/objects/hit/details":          true,
+	"/v1/objects/hit/raw":              true,
+	"/v1/security_issues":              true,
+	"/v1/security_issues/count":        true,
+	"/v1/security_issues/groups":       true,
+	"/v1/security_issues/groups_count": true,
+}
+
+// apiReadRequest reports whether an API request only reads. Anything it does
+// not know to be a read counts as a write, for read_only and the audit log.
+func apiReadRequest(method, uri string) bool {
+	path, _, _ := strings.Cut(uri, "?")
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	case http.MethodPost:
+		// The integration test endpoint is not a read: it sends a real
+		// notification to the integration's receivers.
+		return apiReadPOSTPaths[path]
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package wallarm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	wallarm "github.com/wallarm/wallarm-go"
+)
+
+// TestReadOnlyClient_RefusesEveryWrite calls each wallarm.API method named
+// like a write on a readOnlyClient around a nil client: a method that is not
+// overridden panics instead of returning errReadOnly.
+func TestReadOnlyClient_RefusesEveryWrite(t *testing.T) {
+	writes := regexp.MustCompile(`Create|Update|Delete|Put`)
+	client := reflect.ValueOf(newReadOnlyClient(nil))
+	apiType := reflect.TypeOf((*wallarm.API)(nil)).Elem()
+
+	var checked int
+	for i := range apiType.NumMethod() {
+		m := apiType.Method(i)
+		if !writes.MatchString(m.Name) {
+			continue
+		}
+		checked++
+		t.Run(m.Name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("%s is not refused by readOnlyClient", m.Name)
+				}
+			}()
+			method := client.MethodByName(m.Name)
+			args := make([]reflect.Value, method.Type().NumIn())
+			for j := range args {
+				args[j] = reflect.Zero(method.Type().In(j))
+			}
+			out := method.Call(args)
+			err, _ := out[len(out)-1].Interface().(error)
+			if !errors.Is(err, errReadOnly) {
+				t.Errorf("%s err = %v, want errReadOnly", m.Name, err)
+			}
+		})
+	}
+	if checked < 40 {
+		t.Errorf("checked %d write methods, the pattern no longer matches wallarm.API", checked)
+	}
+}
+
+func TestAPIReadRequest(t *testing.T) {
+	cases := map[string]bool{
+		"GET /v2/integration?clientid=1":  true,
+		"POST /v1/objects/hint":           true,
+		"POST /v1/objects/hint/count":     true,
+		"POST /v1/user":                   true,
+		"POST /v1/objects/pool/create":    false,
+		"PUT /v3/hint/5":                  false,
+		"POST /v2/integration/17/test":    false,
+		"POST /v1/objects/hint/create":    false,
+		"POST /v2/integration":            false,
+		"PUT /v2/integration/17":          false,
+		"DELETE /v2/integration/17":       false,
+		"POST /v2/integration/17/test/..": false,
+	}
+	for req, want := range cases {
+		method, uri, _ := strings.Cut(req, " ")
+		if got := apiReadRequest(method, uri); got != want {
+			t.Errorf("apiReadRequest(%s) = %t, want %t", req, got, want)
+		}
+	}
+}
+
+func TestFakeAPI_ReadOnly(t *testing.T) {
+	srv := testFakeAPIServer(t)
+	meta := testFakeAPIMeta(t, srv, map[string]any{"read_only": true})
+	ctx := context.Background()
+	res := resourceWallarmMode()
+
+	// Refresh and import of an existing rule work.
+	ruleID := srv.SeedHint(map[string]any{"type": "wallarm_mode", "mode": "block", "action": []any{}})
+	d := res.Data(nil)
+	d.SetId(fmt.Sprintf("1/0/%d/block", ruleID)) // Read looks the rule up by rule_id alone
+	states, err := res.Importer.StateContext(ctx, d, meta)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if diags := res.ReadContext(ctx, states[0], meta); diags.HasError() || states[0].Id() == "" {
+		t.Fatalf("Read: %v, id %q", diags, states[0].Id())
+	}
+
+	// Create fails before its request.
+	create := schema.TestResourceDataRaw(t, res.Schema, map[string]any{
+		"mode": "monitoring",
+		"action": []any{map[string]any{
+			"type":  "iequal",
+			"value": "readonly.example.com",
+			"point": map[string]any{"header": "HOST"},
+		}},
+	})
+	create.MarkNewResource()
+	diags := res.CreateContext(ctx, create, meta)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "read_only = true") {
+		t.Errorf("Create diags = %v, want a read_only error", diags)
+	}
+	if diags := res.DeleteContext(ctx, states[0], meta); !diags.HasError() {
+		t.Error("Delete succeeded in read-only mode")
+	}
+
+	// wallarm-go reads with POST too; writes go to /create, /delete or v3 PUT.
+	for _, req := range srv.Requests() {
+		if strings.HasPrefix(req, "PUT ") || strings.HasPrefix(req, "DELETE ") ||
+			strings.HasSuffix(req, "/create") || strings.HasSuffix(req, "/delete") {
+			t.Errorf("write request sent in read-only mode: %s", req)
+		}
+	}
+	if n := srv.HintCount(); n != 1 {
+		t.Errorf("%d hints stored, want the seeded one", n)
+	}
+}
@@ -312,6 +312,13 @@ func findActionByConditions(client wallarm.API, clientID int, conditions []walla
 	targetHash := resourcerule.ConditionsHash(conditions)
 
 	empty := len(conditions) == 0
+	if cached, ok := client.(*CachedClient); ok {
+		entry, err := cached.actionIndex.Find(cached.API, actionIndexScope{clientID: clientID, empty: empty}, targetHash)
+		if err != nil || entry == nil {
+			return 0, nil, false, err
+		}
+		return entry.ID, entry, true, nil
+	}
 	params := &wallarm.ActionListParams{
 		Filter: &wallarm.ActionListFilter{
 			Clientid: []int{clientID},
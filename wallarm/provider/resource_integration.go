@@ -0,0 +1,141 @@
+package wallarm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/wallarm/wallarm-go"
+)
+
+// resourceWallarmIntegration manages an integration of any type the Cloud
+// supports, including types this provider has no dedicated resource for. The
+// target is passed through as-is: a string (target) or a JSON object (config
+// and config_json).
+func resourceWallarmIntegration() *schema.Resource {
+	r := newIntegrationResource(&genericIntegration)
+	r.Importer = &schema.ResourceImporter{StateContext: importGenericIntegration}
+	return r
+}
+
+var genericIntegration = integrationSpec{
+	DefaultName:        "Integration managed by Terraform",
+	EventActiveDefault: true,
+	Transport: map[string]*schema.Schema{
+		"type": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+
+		"target": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Sensitive:     true,
+			ConflictsWith: []string{"config", "config_json"},
+		},
+
+		"config": {
+			Type:          schema.TypeMap,
+			Optional:      true,
+			Sensitive:     true,
+			Elem:          &schema.Schema{Type: schema.TypeString},
+			ConflictsWith: []string{"target"},
+		},
+
+		"config_json": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Sensitive:     true,
+			ValidateFunc:  validation.StringIsJSON,
+			ConflictsWith: []string{"target"},
+		},
+	},
+	Secrets:       []string{"target", "config", "config_json"},
+	ExpandTarget:  expandGenericTarget,
+	FlattenTarget: flattenGenericTarget,
+}
+
+// expandGenericTarget returns target when set, otherwise the config map with
+// the config_json object merged over it.
+func expandGenericTarget(d *schema.ResourceData) any {
+	if target := d.Get("target").(string); target != "" {
+		return target
+	}
+	obj := make(map[string]any)
+	maps.Copy(obj, d.Get("config").(map[string]any))
+	if raw := d.Get("config_json").(string); raw != "" {
+		var typed map[string]any
+		// Validated by StringIsJSON; a non-object document is ignored.
+		if err := json.Unmarshal([]byte(raw), &typed); err == nil {
+			maps.Copy(obj, typed)
+		}
+	}
+	return obj
+}
+
+// flattenGenericTarget reads the target back. config only tracks the keys
+// already in the configuration, or every string field after import, so keys
+// the Cloud adds do not show up as drift; config_json is never read back.
+func flattenGenericTarget(d *schema.ResourceData, obj *wallarm.IntegrationObject) {
+	if s, ok := obj.Target.(string); ok {
+		d.Set("target", integrationSecret(d, "target", s))
+		return
+	}
+	target := integrationTargetMap(obj)
+	if target == nil {
+		return
+	}
+
+	prior := d.Get("config").(map[string]any)
+	config := make(map[string]any)
+	for k, v := range target {
+		if len(prior) > 0 {
+			if _, ok := prior[k]; !ok {
+				continue
+			}
+		} else if _, ok := v.(string); !ok {
+			continue
+		}
+		s := fmt.Sprint(v)
+		if isMaskedSecret(s) {
+			if p, ok := prior[k]; ok {
+				s = p.(string)
+			}
+		}
+		config[k] = s
+	}
+	if len(prior) == 0 && d.Get("config_json").(string) != "" {
+		// Every field is managed through config_json.
+		return
+	}
+	d.Set("config", config)
+}
+
+// importGenericIntegration imports {client_id}/{type}/{integration_id} for any
+// integration type.
+func importGenericIntegration(_ context.Context, d *schema.ResourceData, _ any) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 4)
+	if len(parts) != 3 || parts[1] == "" {
+		return nil, fmt.Errorf("invalid id (%q) specified, should be in format \"{client_id}/{type}/{integration_id}\"", d.Id())
+	}
+	clientID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid client_id: %w", err)
+	}
+	integrationID, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid integration_id: %w", err)
+	}
+	d.Set("client_id", clientID)
+	d.Set("type", parts[1])
+	d.Set("integration_id", integrationID)
+	d.SetId(fmt.Sprintf("%d/%s/%d", clientID, parts[1], integrationID))
+	return []*schema.ResourceData{d}, nil
+}
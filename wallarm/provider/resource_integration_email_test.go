@@ -2,9 +2,12 @@ package wallarm
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccIntegrationEmailRequiredFields(t *testing.T) {
@@ -34,6 +37,18 @@ func TestAccIntegrationEmailFullSettings(t *testing.T) {
 		PreCheck:  func() { testAccPreCheck(t) },
 		Providers: testAccProviders,
 		Steps: []resource.TestStep{
+			{
+				Config: testWallarmIntegrationEmailFullConfig("tf-test-"+rnd, rnd, "true"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "active", "true"),
+					resource.TestCheckResourceAttr(name, "event.#", "7"),
+				),
+			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 			{
 				Config: testWallarmIntegrationEmailFullConfig("tf-test-"+rnd, rnd, "false"),
 				Check: resource.ComposeTestCheckFunc(
@@ -44,6 +59,17 @@ func TestAccIntegrationEmailFullSettings(t *testing.T) {
 					resource.TestCheckResourceAttr(name, "event.#", "7"),
 				),
 			},
+			{
+				// Without prior state Read keeps only the events the API
+				// reports as active, so the inactive ones are not imported.
+				ResourceName: name,
+				ImportState:  true,
+				ImportStateCheck: testAccCheckImportedEvents(map[string]bool{
+					"system":                              true,
+					"api_discovery_hourly_changes_report": true,
+					"report_monthly":                      true,
+				}),
+			},
 		},
 	})
 }
@@ -187,3 +213,24 @@ resource "wallarm_integration_email" "test" {
 
 }`, name, email, globalActive, active)
 }
+
+// testAccCheckImportedEvents checks that the imported integration holds exactly
+// the given event_type -> active set.
+func testAccCheckImportedEvents(want map[string]bool) resource.ImportStateCheckFunc {
+	return func(states []*terraform.InstanceState) error {
+		if len(states) != 1 {
+			return fmt.Errorf("expected one imported state, got %d", len(states))
+		}
+		attrs := states[0].Attributes
+		got := map[string]bool{}
+		for k, v := range attrs {
+			if strings.HasPrefix(k, "event.") && strings.HasSuffix(k, ".event_type") {
+				got[v] = attrs[strings.TrimSuffix(k, "event_type")+"active"] == "true"
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("imported events: got %v, want %v", got, want)
+		}
+		return nil
+	}
+}
@@ -1,237 +1,33 @@
 package wallarm
 
 import (
-	"context"
-	"fmt"
-
-	"github.com/wallarm/wallarm-go"
-
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceWallarmOpsGenie() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceWallarmOpsGenieCreate,
-		ReadContext:   resourceWallarmOpsGenieRead,
-		UpdateContext: resourceWallarmOpsGenieUpdate,
-		DeleteContext: resourceWallarmOpsGenieDelete,
-
-		Importer: &schema.ResourceImporter{
-			StateContext: importIntegration("opsgenie"),
-		},
-
-		Schema: map[string]*schema.Schema{
-			"client_id": defaultClientIDWithValidationSchema,
-
-			"active": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
-			},
-
-			"integration_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-
-			"created_by": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-
-			"type": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-
-			"is_active": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-
-			"name": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "OpsGenie integration managed by Terraform",
-			},
-
-			"api_token": {
-				Type:      schema.TypeString,
-				Required:  true,
-				Sensitive: true,
-			},
-
-			"api_url": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Sensitive:    true,
-				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
-			},
-
-			"event": {
-				Type:     schema.TypeSet,
-				Optional: true,
-				MaxItems: 7,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"event_type": {
-							Type:     schema.TypeString,
-							Optional: true,
-							ValidateFunc: validation.StringInSlice([]string{
-								"system",
-								"rules_and_triggers",
-								"security_issue_critical",
-								"security_issue_high",
-								"security_issue_medium",
-								"security_issue_low",
-								"security_issue_info",
-							}, false),
-						},
-						"active": {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  true,
-						},
-					},
-				},
-			},
-		},
-	}
+	return newIntegrationResource(&opsGenieIntegration)
 }
 
-func resourceWallarmOpsGenieCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	clientID, err := retrieveClientID(d, m)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	name := d.Get("name").(string)
-	apiURL := d.Get("api_url").(string)
-	apiToken := d.Get("api_token").(string)
-	active := d.Get("active").(bool)
-	events := expandWallarmEventToIntEvents(d.Get("event"), "opsgenie")
-
-	opsGenieBody := wallarm.IntegrationCreate{
-		Name:   name,
-		Active: active,
-		Target: &wallarm.IntegrationTokenAPITarget{
-			Token: apiToken,
-			API:   apiURL,
+var opsGenieIntegration = integrationSpec{
+	APIType:            "opsgenie",
+	DefaultName:        "OpsGenie integration managed by Terraform",
+	Events:             chatIntegrationEvents,
+	EventActiveDefault: true,
+	Transport: map[string]*schema.Schema{
+		"api_token": {
+			Type:      schema.TypeString,
+			Required:  true,
+			Sensitive: true,
 		},
-		Clientid: clientID,
-		Type:     "opsgenie",
-		Events:   events,
-	}
-
-	createRes, err := client.IntegrationCreate(&opsGenieBody)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	d.Set("integration_id", createRes.Body.ID)
-
-	resID := fmt.Sprintf("%d/%s/%d", clientID, createRes.Body.Type, createRes.Body.ID)
-	d.SetId(resID)
-
-	return resourceWallarmOpsGenieRead(ctx, d, m)
-}
-
-func resourceWallarmOpsGenieRead(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	clientID, err := retrieveClientID(d, m)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	opsGenie, err := client.IntegrationRead(clientID, d.Get("integration_id").(int))
-	if err != nil {
-		if isNotFoundError(err) {
-			d.SetId("")
-			return nil
-		}
-		return diag.FromErr(err)
-	}
-
-	d.Set("integration_id", opsGenie.ID)
-	d.Set("is_active", opsGenie.Active)
-	d.Set("name", opsGenie.Name)
-	d.Set("created_by", opsGenie.CreatedBy)
-	d.Set("type", opsGenie.Type)
-	d.Set("client_id", clientID)
-
-	return nil
-}
-
-func resourceWallarmOpsGenieUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	clientID, err := retrieveClientID(d, m)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	opsgenie, err := client.IntegrationRead(clientID, d.Get("integration_id").(int))
-	if err != nil {
-		if isNotFoundError(err) {
-			d.SetId("")
-			return nil
-		}
-		return diag.FromErr(err)
-	}
-
-	if d.HasChange("event") {
-		// When events change, API requires the full configuration
-		fullBody := wallarm.IntegrationCreate{
-			Name:   d.Get("name").(string),
-			Active: d.Get("active").(bool),
-			Target: &wallarm.IntegrationTokenAPITarget{
-				Token: d.Get("api_token").(string),
-				API:   d.Get("api_url").(string),
-			},
-			Events: expandWallarmEventToIntEvents(d.Get("event"), "opsgenie"),
-			Type:   "opsgenie",
-		}
-		updateRes, err := client.IntegrationUpdate(&fullBody, opsgenie.ID)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-		d.Set("integration_id", updateRes.Body.ID)
-		resID := fmt.Sprintf("%d/%s/%d", clientID, updateRes.Body.Type, updateRes.Body.ID)
-		d.SetId(resID)
-	} else {
-		updateBody := make(map[string]any)
-		if d.HasChange("name") {
-			updateBody["name"] = d.Get("name").(string)
-		}
-		if d.HasChange("active") {
-			updateBody["active"] = d.Get("active").(bool)
-		}
-		if d.HasChange("api_token") || d.HasChange("api_url") {
-			updateBody["target"] = &wallarm.IntegrationTokenAPITarget{
-				Token: d.Get("api_token").(string),
-				API:   d.Get("api_url").(string),
-			}
-		}
-		if len(updateBody) > 0 {
-			updateRes, err := client.IntegrationPartialUpdate(opsgenie.ID, updateBody)
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			d.Set("integration_id", updateRes.Body.ID)
-			resID := fmt.Sprintf("%d/%s/%d", clientID, updateRes.Body.Type, updateRes.Body.ID)
-			d.SetId(resID)
-		}
-	}
-
-	return resourceWallarmOpsGenieRead(ctx, d, m)
-}
-
-func resourceWallarmOpsGenieDelete(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	integrationID := d.Get("integration_id").(int)
-	if err := client.IntegrationDelete(integrationID); err != nil {
-		return diag.FromErr(err)
-	}
-
-	return nil
+		"api_url": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Sensitive:    true,
+			ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+		},
+	},
+	Secrets:       []string{"api_token", "api_url"},
+	ExpandTarget:  expandTokenAPITarget,
+	FlattenTarget: flattenTokenAPITarget,
 }
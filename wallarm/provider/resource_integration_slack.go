@@ -1,221 +1,28 @@
 package wallarm
 
 import (
-	"context"
-	"fmt"
-
-	"github.com/wallarm/wallarm-go"
-
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
-// nolint:dupl
 func resourceWallarmSlack() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceWallarmSlackCreate,
-		ReadContext:   resourceWallarmSlackRead,
-		UpdateContext: resourceWallarmSlackUpdate,
-		DeleteContext: resourceWallarmSlackDelete,
-
-		Importer: &schema.ResourceImporter{
-			StateContext: importIntegration("slack"),
-		},
-
-		Schema: map[string]*schema.Schema{
-			"client_id": defaultClientIDWithValidationSchema,
-
-			"active": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
-			},
-
-			"integration_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-
-			"created_by": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-
-			"type": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-
-			"is_active": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-
-			"name": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "Slack integration managed by Terraform",
-			},
-
-			"webhook_url": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
-				Sensitive:    true,
-			},
-
-			"event": {
-				Type:     schema.TypeSet,
-				Optional: true,
-				MaxItems: 7,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"event_type": {
-							Type:     schema.TypeString,
-							Optional: true,
-							ValidateFunc: validation.StringInSlice([]string{
-								"system",
-								"rules_and_triggers",
-								"security_issue_critical",
-								"security_issue_high",
-								"security_issue_medium",
-								"security_issue_low",
-								"security_issue_info",
-							}, false),
-						},
-						"active": {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  true,
-						},
-					},
-				},
-			},
-		},
-	}
+	return newIntegrationResource(&slackIntegration)
 }
 
-func resourceWallarmSlackCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	clientID, err := retrieveClientID(d, m)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	name := d.Get("name").(string)
-	webhookURL := d.Get("webhook_url").(string)
-	active := d.Get("active").(bool)
-	events := expandWallarmEventToIntEvents(d.Get("event"), "slack")
-
-	slackBody := wallarm.IntegrationCreate{
-		Name:     name,
-		Active:   active,
-		Target:   webhookURL,
-		Clientid: clientID,
-		Type:     "slack",
-		Events:   events,
-	}
-
-	createRes, err := client.IntegrationCreate(&slackBody)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	d.Set("integration_id", createRes.Body.ID)
-
-	resID := fmt.Sprintf("%d/%s/%d", clientID, createRes.Body.Type, createRes.Body.ID)
-	d.SetId(resID)
-
-	return resourceWallarmSlackRead(ctx, d, m)
-}
-
-func resourceWallarmSlackRead(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	clientID, err := retrieveClientID(d, m)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	slack, err := client.IntegrationRead(clientID, d.Get("integration_id").(int))
-	if err != nil {
-		if isNotFoundError(err) {
-			d.SetId("")
-			return nil
-		}
-		return diag.FromErr(err)
-	}
-	d.Set("integration_id", slack.ID)
-	d.Set("is_active", slack.Active)
-	d.Set("name", slack.Name)
-	d.Set("created_by", slack.CreatedBy)
-	d.Set("type", slack.Type)
-	d.Set("client_id", clientID)
-
-	return nil
-}
-
-func resourceWallarmSlackUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	clientID, err := retrieveClientID(d, m)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	slack, err := client.IntegrationRead(clientID, d.Get("integration_id").(int))
-	if err != nil {
-		if isNotFoundError(err) {
-			d.SetId("")
-			return nil
-		}
-		return diag.FromErr(err)
-	}
-
-	if d.HasChange("event") {
-		// When events change, API requires the full configuration
-		fullBody := wallarm.IntegrationCreate{
-			Name:   d.Get("name").(string),
-			Active: d.Get("active").(bool),
-			Target: d.Get("webhook_url").(string),
-			Events: expandWallarmEventToIntEvents(d.Get("event"), "slack"),
-			Type:   "slack",
-		}
-		updateRes, err := client.IntegrationUpdate(&fullBody, slack.ID)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-		d.Set("integration_id", updateRes.Body.ID)
-		resID := fmt.Sprintf("%d/%s/%d", clientID, updateRes.Body.Type, updateRes.Body.ID)
-		d.SetId(resID)
-	} else {
-		updateBody := make(map[string]any)
-		if d.HasChange("name") {
-			updateBody["name"] = d.Get("name").(string)
-		}
-		if d.HasChange("active") {
-			updateBody["active"] = d.Get("active").(bool)
-		}
-		if d.HasChange("webhook_url") {
-			updateBody["target"] = d.Get("webhook_url").(string)
-		}
-		if len(updateBody) > 0 {
-			updateRes, err := client.IntegrationPartialUpdate(slack.ID, updateBody)
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			d.Set("integration_id", updateRes.Body.ID)
-			resID := fmt.Sprintf("%d/%s/%d", clientID, updateRes.Body.Type, updateRes.Body.ID)
-			d.SetId(resID)
-		}
-	}
-
-	return resourceWallarmSlackRead(ctx, d, m)
-}
-
-func resourceWallarmSlackDelete(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	integrationID := d.Get("integration_id").(int)
-	if err := client.IntegrationDelete(integrationID); err != nil {
-		return diag.FromErr(err)
-	}
-
-	return nil
+var slackIntegration = integrationSpec{
+	APIType:            "slack",
+	DefaultName:        "Slack integration managed by Terraform",
+	Events:             chatIntegrationEvents,
+	EventActiveDefault: true,
+	Transport: map[string]*schema.Schema{
+		"webhook_url": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Sensitive:    true,
+			ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+		},
+	},
+	Secrets:       []string{"webhook_url"},
+	ExpandTarget:  expandStringTarget("webhook_url"),
+	FlattenTarget: flattenStringTarget("webhook_url"),
 }
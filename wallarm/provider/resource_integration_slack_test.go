@@ -43,6 +43,13 @@ func TestAccIntegrationSlackFullSettings(t *testing.T) {
 					resource.TestCheckResourceAttr(name, "event.#", "7"),
 				),
 			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The webhook URL may be masked.
+				ImportStateVerifyIgnore: []string{"webhook_url"},
+			},
 		},
 	})
 }
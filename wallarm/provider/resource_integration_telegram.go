@@ -1,218 +1,71 @@
 package wallarm
 
 import (
-	"context"
-	"fmt"
-
-	"github.com/wallarm/wallarm-go"
-
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/wallarm/wallarm-go"
 )
 
 func resourceWallarmTelegram() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceWallarmTelegramCreate,
-		ReadContext:   resourceWallarmTelegramRead,
-		UpdateContext: resourceWallarmTelegramUpdate,
-		DeleteContext: resourceWallarmTelegramDelete,
+	return newIntegrationResource(&telegramIntegration)
+}
 
-		Importer: &schema.ResourceImporter{
-			StateContext: importIntegration("telegram"),
+// telegramIntegration is created through the dedicated Telegram endpoint. The
+// API does not return the bot token or chat data, and the target cannot be
+// changed afterwards: telegram_username and chat_data are ForceNew and keep
+// their configured values.
+var telegramIntegration = integrationSpec{
+	APIType:     "telegram",
+	DefaultName: "Telegram integration managed by Terraform",
+	Events: []string{
+		"system",
+		"rules_and_triggers",
+		"security_issue_critical",
+		"security_issue_high",
+		"security_issue_medium",
+		"security_issue_low",
+		"security_issue_info",
+		"report_daily",
+		"report_weekly",
+		"report_monthly",
+	},
+	EventActiveDefault: true,
+	Transport: map[string]*schema.Schema{
+		"telegram_username": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
 		},
-
-		Schema: map[string]*schema.Schema{
-			"client_id": defaultClientIDWithValidationSchema,
-
-			"active": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
-			},
-
-			"integration_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-
-			"created_by": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-
-			"type": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-
-			"is_active": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-
-			"name": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "Telegram integration managed by Terraform",
-			},
-
-			"telegram_username": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-			},
-
-			"chat_data": {
-				Type:      schema.TypeString,
-				Required:  true,
-				Sensitive: true,
-				ForceNew:  true,
-			},
-
-			"event": {
-				Type:     schema.TypeSet,
-				Optional: true,
-				MaxItems: 10,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"event_type": {
-							Type:     schema.TypeString,
-							Optional: true,
-							ValidateFunc: validation.StringInSlice([]string{
-								"system",
-								"rules_and_triggers",
-								"security_issue_critical",
-								"security_issue_high",
-								"security_issue_medium",
-								"security_issue_low",
-								"security_issue_info",
-								"report_daily",
-								"report_weekly",
-								"report_monthly",
-							}, false),
-						},
-						"active": {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  true,
-						},
-					},
-				},
-			},
+		"chat_data": {
+			Type:      schema.TypeString,
+			Required:  true,
+			Sensitive: true,
+			ForceNew:  true,
 		},
-	}
+	},
+	Secrets: []string{"chat_data"},
+	Create:  createTelegramIntegration,
 }
 
-func resourceWallarmTelegramCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	clientID, err := retrieveClientID(d, m)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	telegramUsername := d.Get("telegram_username").(string)
-	chatData := d.Get("chat_data").(string)
-
+// createTelegramIntegration creates the integration, then sets its name,
+// events and active state, which the Telegram endpoint does not accept.
+func createTelegramIntegration(spec *integrationSpec, client wallarm.API, d *schema.ResourceData, clientID int) (*wallarm.IntegrationCreateResp, error) {
 	tgBody := wallarm.TelegramIntegrationCreate{
-		Name:     telegramUsername,
+		Name:     d.Get("telegram_username").(string),
 		Clientid: clientID,
-		ChatData: chatData,
+		ChatData: d.Get("chat_data").(string),
 	}
-
 	createRes, err := client.TelegramIntegrationCreate(&tgBody)
 	if err != nil {
-		return diag.FromErr(err)
+		return nil, err
 	}
 
-	integrationID := createRes.Body.ID
-	d.Set("integration_id", integrationID)
-
-	resID := fmt.Sprintf("%d/%s/%d", clientID, createRes.Body.Type, integrationID)
-	d.SetId(resID)
-
-	// After creation, set integration name, events and active state via update
 	updateBody := map[string]any{
 		"name":   d.Get("name").(string),
 		"active": d.Get("active").(bool),
-		"events": expandWallarmEventToIntEvents(d.Get("event"), "telegram"),
-	}
-	if _, err := client.IntegrationPartialUpdate(integrationID, updateBody); err != nil {
-		return diag.FromErr(err)
-	}
-
-	return resourceWallarmTelegramRead(ctx, d, m)
-}
-
-func resourceWallarmTelegramRead(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	clientID, err := retrieveClientID(d, m)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	telegram, err := client.IntegrationRead(clientID, d.Get("integration_id").(int))
-	if err != nil {
-		if isNotFoundError(err) {
-			d.SetId("")
-			return nil
-		}
-		return diag.FromErr(err)
-	}
-
-	d.Set("integration_id", telegram.ID)
-	d.Set("is_active", telegram.Active)
-	d.Set("name", telegram.Name)
-	d.Set("created_by", telegram.CreatedBy)
-	d.Set("type", telegram.Type)
-	d.Set("client_id", clientID)
-
-	return nil
-}
-
-func resourceWallarmTelegramUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	clientID, err := retrieveClientID(d, m)
-	if err != nil {
-		return diag.FromErr(err)
+		"events": expandWallarmEventToIntEvents(d.Get("event"), spec.Events),
 	}
-
-	telegram, err := client.IntegrationRead(clientID, d.Get("integration_id").(int))
-	if err != nil {
-		if isNotFoundError(err) {
-			d.SetId("")
-			return nil
-		}
-		return diag.FromErr(err)
-	}
-
-	updateBody := make(map[string]any)
-	if d.HasChange("name") {
-		updateBody["name"] = d.Get("name").(string)
-	}
-	if d.HasChange("active") {
-		updateBody["active"] = d.Get("active").(bool)
-	}
-	if d.HasChange("event") {
-		updateBody["events"] = expandWallarmEventToIntEvents(d.Get("event"), "telegram")
+	if _, err := client.IntegrationPartialUpdate(createRes.Body.ID, updateBody); err != nil {
+		return nil, err
 	}
-	if len(updateBody) > 0 {
-		updateRes, err := client.IntegrationPartialUpdate(telegram.ID, updateBody)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-		d.Set("integration_id", updateRes.Body.ID)
-		resID := fmt.Sprintf("%d/%s/%d", clientID, updateRes.Body.Type, updateRes.Body.ID)
-		d.SetId(resID)
-	}
-
-	return resourceWallarmTelegramRead(ctx, d, m)
-}
-
-func resourceWallarmTelegramDelete(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	integrationID := d.Get("integration_id").(int)
-	if err := client.IntegrationDelete(integrationID); err != nil {
-		return diag.FromErr(err)
-	}
-
-	return nil
+	return createRes, nil
 }
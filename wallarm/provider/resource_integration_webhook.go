@@ -1,314 +1,134 @@
 package wallarm
 
 import (
-	"context"
 	"fmt"
 
-	"github.com/wallarm/wallarm-go"
-
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/wallarm/wallarm-go"
 )
 
 func resourceWallarmWebhook() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceWallarmWebhookCreate,
-		ReadContext:   resourceWallarmWebhookRead,
-		UpdateContext: resourceWallarmWebhookUpdate,
-		DeleteContext: resourceWallarmWebhookDelete,
+	return newIntegrationResource(&webhookIntegration)
+}
 
-		Importer: &schema.ResourceImporter{
-			StateContext: importIntegration("web_hooks"),
+var webhookIntegration = integrationSpec{
+	APIType:            "web_hooks",
+	DefaultName:        "Webhook integration managed by Terraform",
+	Events:             siemIntegrationEvents,
+	EventActiveDefault: true,
+	Transport: map[string]*schema.Schema{
+		"http_method": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"PUT", "POST"}, false),
+			Default:      "POST",
 		},
 
-		CustomizeDiff: validateWithHeadersOnlySiem(),
-
-		Schema: map[string]*schema.Schema{
-			"client_id": defaultClientIDWithValidationSchema,
-
-			"active": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
-			},
-
-			"integration_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-
-			"created_by": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-
-			"type": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-
-			"is_active": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-
-			"name": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "Webhook integration managed by Terraform",
-			},
-
-			"http_method": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: validation.StringInSlice([]string{"PUT", "POST"}, false),
-				Default:      "POST",
-			},
-
-			"format": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: validation.StringInSlice([]string{"json", "jsonl"}, false),
-				Default:      "json",
-			},
-
-			"webhook_url": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.IsURLWithHTTPS,
-				Sensitive:    true,
-			},
+		"format": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"json", "jsonl"}, false),
+			Default:      "json",
+		},
 
-			"ca_file": {
-				Type:      schema.TypeString,
-				Optional:  true,
-				Default:   "",
-				Sensitive: true,
-			},
+		"webhook_url": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.IsURLWithHTTPS,
+			Sensitive:    true,
+		},
 
-			"ca_verify": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  true,
-			},
+		"ca_file": {
+			Type:      schema.TypeString,
+			Optional:  true,
+			Default:   "",
+			Sensitive: true,
+		},
 
-			"timeout": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  15,
-			},
+		"ca_verify": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
 
-			"open_timeout": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  20,
-			},
+		"timeout": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  15,
+		},
 
-			"event": {
-				Type:     schema.TypeSet,
-				Optional: true,
-				MaxItems: 9,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"event_type": {
-							Type:     schema.TypeString,
-							Optional: true,
-							ValidateFunc: validation.StringInSlice([]string{
-								"siem",
-								"rules_and_triggers",
-								"number_of_requests_per_hour",
-								"security_issue_critical",
-								"security_issue_high",
-								"security_issue_medium",
-								"security_issue_low",
-								"security_issue_info",
-								"system",
-							}, false),
-						},
-						"active": {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  true,
-						},
-						"with_headers": {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Description: "Send requests with headers. Only applicable to the 'siem' event type.",
-						},
-					},
-				},
-			},
+		"open_timeout": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  20,
+		},
 
-			"headers": {
-				Type:      schema.TypeMap,
-				Optional:  true,
-				Sensitive: true,
-				Elem:      &schema.Schema{Type: schema.TypeString},
-			},
+		"headers": {
+			Type:      schema.TypeMap,
+			Optional:  true,
+			Sensitive: true,
+			Elem:      &schema.Schema{Type: schema.TypeString},
 		},
-	}
+	},
+	Secrets:       []string{"webhook_url", "ca_file", "headers"},
+	ExpandTarget:  expandWebhookTarget,
+	FlattenTarget: flattenWebhookTarget,
 }
 
-func resourceWallarmWebhookCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	clientID, err := retrieveClientID(d, m)
-	if err != nil {
-		return diag.FromErr(err)
+func expandWebhookTarget(d *schema.ResourceData) any {
+	return &wallarm.IntegrationWithAPITarget{
+		URL:         d.Get("webhook_url").(string),
+		HTTPMethod:  d.Get("http_method").(string),
+		Timeout:     d.Get("timeout").(int),
+		OpenTimeout: d.Get("open_timeout").(int),
+		CaFile:      d.Get("ca_file").(string),
+		CaVerify:    d.Get("ca_verify").(bool),
+		Headers:     d.Get("headers").(map[string]any),
+		Format:      d.Get("format").(string),
+		Subtype:     "web_hooks",
 	}
-	name := d.Get("name").(string)
-	active := d.Get("active").(bool)
-	webhookURL := d.Get("webhook_url").(string)
-	method := d.Get("http_method").(string)
-	caFile := d.Get("ca_file").(string)
-	caVerify := d.Get("ca_verify").(bool)
-	timeout := d.Get("timeout").(int)
-	openTimeout := d.Get("open_timeout").(int)
-	headers := d.Get("headers").(map[string]any)
-	format := d.Get("format").(string)
-
-	events := expandWallarmEventToIntEvents(d.Get("event"), "web_hooks")
+}
 
-	webhookBody := wallarm.IntegrationWithAPICreate{
-		Name:   name,
-		Active: active,
-		Target: &wallarm.IntegrationWithAPITarget{
-			URL:         webhookURL,
-			HTTPMethod:  method,
-			Timeout:     timeout,
-			OpenTimeout: openTimeout,
-			CaFile:      caFile,
-			CaVerify:    caVerify,
-			Headers:     headers,
-			Format:      format,
-			Subtype:     "web_hooks",
-		},
-		Clientid: clientID,
-		Type:     "web_hooks",
-		Events:   events,
+func flattenWebhookTarget(d *schema.ResourceData, obj *wallarm.IntegrationObject) {
+	target := integrationTargetMap(obj)
+	if target == nil {
+		return
 	}
-
-	createRes, err := client.IntegrationWithAPICreate(&webhookBody)
-	if err != nil {
-		return diag.FromErr(err)
+	d.Set("webhook_url", integrationSecret(d, "webhook_url", targetString(target, "url")))
+	if method := targetString(target, "http_method"); method != "" {
+		d.Set("http_method", method)
 	}
-
-	d.Set("integration_id", createRes.Body.ID)
-
-	resID := fmt.Sprintf("%d/%s/%d", clientID, createRes.Body.Type, createRes.Body.ID)
-	d.SetId(resID)
-
-	return resourceWallarmWebhookRead(ctx, d, m)
-}
-
-func resourceWallarmWebhookRead(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	clientID, err := retrieveClientID(d, m)
-	if err != nil {
-		return diag.FromErr(err)
+	if format := targetString(target, "format"); format != "" {
+		d.Set("format", format)
 	}
-	webhook, err := client.IntegrationRead(clientID, d.Get("integration_id").(int))
-	if err != nil {
-		if isNotFoundError(err) {
-			d.SetId("")
-			return nil
-		}
-		return diag.FromErr(err)
+	d.Set("ca_file", integrationSecret(d, "ca_file", targetString(target, "ca_file")))
+	if caVerify, ok := target["ca_verify"].(bool); ok {
+		d.Set("ca_verify", caVerify)
 	}
-
-	d.Set("integration_id", webhook.ID)
-	d.Set("is_active", webhook.Active)
-	d.Set("name", webhook.Name)
-	d.Set("created_by", webhook.CreatedBy)
-	d.Set("type", webhook.Type)
-	d.Set("client_id", clientID)
-
-	return nil
-}
-
-func resourceWallarmWebhookUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	clientID, err := retrieveClientID(d, m)
-	if err != nil {
-		return diag.FromErr(err)
+	if timeout, ok := targetInt(target, "timeout"); ok {
+		d.Set("timeout", timeout)
 	}
-
-	webhook, err := client.IntegrationRead(clientID, d.Get("integration_id").(int))
-	if err != nil {
-		if isNotFoundError(err) {
-			d.SetId("")
-			return nil
-		}
-		return diag.FromErr(err)
+	if openTimeout, ok := targetInt(target, "open_timeout"); ok {
+		d.Set("open_timeout", openTimeout)
 	}
+	d.Set("headers", flattenWebhookHeaders(d, target["headers"]))
+}
 
-	if d.HasChange("event") {
-		// When events change, API requires the full configuration
-		fullBody := wallarm.IntegrationWithAPICreate{
-			Name:   d.Get("name").(string),
-			Active: d.Get("active").(bool),
-			Target: &wallarm.IntegrationWithAPITarget{
-				URL:         d.Get("webhook_url").(string),
-				HTTPMethod:  d.Get("http_method").(string),
-				Timeout:     d.Get("timeout").(int),
-				OpenTimeout: d.Get("open_timeout").(int),
-				CaFile:      d.Get("ca_file").(string),
-				CaVerify:    d.Get("ca_verify").(bool),
-				Headers:     d.Get("headers").(map[string]any),
-				Format:      d.Get("format").(string),
-				Subtype:     "web_hooks",
-			},
-			Type:   "web_hooks",
-			Events: expandWallarmEventToIntEvents(d.Get("event"), "web_hooks"),
-		}
-		updateRes, err := client.IntegrationWithAPIUpdate(&fullBody, webhook.ID)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-		d.Set("integration_id", updateRes.Body.ID)
-		resID := fmt.Sprintf("%d/%s/%d", clientID, updateRes.Body.Type, updateRes.Body.ID)
-		d.SetId(resID)
-	} else {
-		updateBody := make(map[string]any)
-		if d.HasChange("name") {
-			updateBody["name"] = d.Get("name").(string)
-		}
-		if d.HasChange("active") {
-			updateBody["active"] = d.Get("active").(bool)
-		}
-		if d.HasChanges("webhook_url", "http_method", "ca_file", "ca_verify", "timeout", "open_timeout", "headers", "format") {
-			updateBody["target"] = &wallarm.IntegrationWithAPITarget{
-				URL:         d.Get("webhook_url").(string),
-				HTTPMethod:  d.Get("http_method").(string),
-				Timeout:     d.Get("timeout").(int),
-				OpenTimeout: d.Get("open_timeout").(int),
-				CaFile:      d.Get("ca_file").(string),
-				CaVerify:    d.Get("ca_verify").(bool),
-				Headers:     d.Get("headers").(map[string]any),
-				Format:      d.Get("format").(string),
-				Subtype:     "web_hooks",
-			}
-		}
-		if len(updateBody) > 0 {
-			updateRes, err := client.IntegrationPartialUpdate(webhook.ID, updateBody)
-			if err != nil {
-				return diag.FromErr(err)
+// flattenWebhookHeaders converts the API headers object into the headers map.
+// Masked values keep the configured value for that header.
+func flattenWebhookHeaders(d *schema.ResourceData, raw any) map[string]any {
+	apiHeaders, _ := raw.(map[string]any)
+	prior := d.Get("headers").(map[string]any)
+	headers := make(map[string]any, len(apiHeaders))
+	for k, v := range apiHeaders {
+		s := fmt.Sprint(v)
+		if isMaskedSecret(s) {
+			if p, ok := prior[k]; ok {
+				s = p.(string)
 			}
-			d.Set("integration_id", updateRes.Body.ID)
-			resID := fmt.Sprintf("%d/%s/%d", clientID, updateRes.Body.Type, updateRes.Body.ID)
-			d.SetId(resID)
 		}
+		headers[k] = s
 	}
-
-	return resourceWallarmWebhookRead(ctx, d, m)
-}
-
-func resourceWallarmWebhookDelete(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
-	integrationID := d.Get("integration_id").(int)
-	if err := client.IntegrationDelete(integrationID); err != nil {
-		return diag.FromErr(err)
-	}
-
-	return nil
+	return headers
 }
@@ -22,6 +22,30 @@ import (
 
 const ruleTypeSubnet = "subnet"
 
+// ipListSourceFields are the mutually exclusive value fields of the bulk IP
+// list resources, in rule-building order, with their API rule types.
+var ipListSourceFields = []struct {
+	field    string
+	ruleType string
+}{
+	{"ip_range", ruleTypeSubnet},
+	{"country", ruleTypeLocation},
+	{"datacenter", ruleTypeDatacenter},
+	{"proxy_type", ruleTypeProxyType},
+	{"asn", ruleTypeASN},
+}
+
+// ipListConflicts returns every value field except the given one.
+func ipListConflicts(field string) []string {
+	others := make([]string, 0, len(ipListSourceFields)-1)
+	for _, f := range ipListSourceFields {
+		if f.field != field {
+			others = append(others, f.field)
+		}
+	}
+	return others
+}
+
 func resourceWallarmIPList(listType wallarm.IPListType) *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceWallarmIPListCreate(listType),
@@ -31,6 +55,7 @@ func resourceWallarmIPList(listType wallarm.IPListType) *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceWallarmIPListImport(listType),
 		},
+		CustomizeDiff: ipListExpiryCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"client_id": defaultClientIDWithValidationSchema,
 			"ip_range": {
@@ -38,30 +63,41 @@ func resourceWallarmIPList(listType wallarm.IPListType) *schema.Resource {
 				Optional:      true,
 				MaxItems:      IPListMaxSubnets,
 				Elem:          &schema.Schema{Type: schema.TypeString},
-				ConflictsWith: []string{"country", "datacenter", "proxy_type"},
+				ConflictsWith: ipListConflicts("ip_range"),
 			},
 			"country": {
 				Type:          schema.TypeList,
 				Optional:      true,
 				Elem:          &schema.Schema{Type: schema.TypeString},
-				ConflictsWith: []string{"ip_range", "datacenter", "proxy_type"},
+				ConflictsWith: ipListConflicts("country"),
 			},
 			"datacenter": {
 				Type:     schema.TypeList,
 				Optional: true,
 				Elem: &schema.Schema{
 					Type:         schema.TypeString,
-					ValidateFunc: validation.StringInSlice([]string{"alibaba", "aws", "azure", "docean", "gce", "hetzner", "huawei", "ibm", "linode", "oracle", "ovh", "plusserver", "rackspace", "tencent"}, false),
+					ValidateFunc: validateIPListSource(ruleTypeDatacenter),
 				},
-				ConflictsWith: []string{"ip_range", "country", "proxy_type"},
+				ConflictsWith: ipListConflicts("datacenter"),
 			},
 			"proxy_type": {
 				Type:          schema.TypeList,
 				Optional:      true,
-				ConflictsWith: []string{"ip_range", "country", "datacenter"},
+				ConflictsWith: ipListConflicts("proxy_type"),
 				Elem: &schema.Schema{
 					Type:         schema.TypeString,
-					ValidateFunc: validation.StringInSlice([]string{"DCH", "MIP", "PUB", "WEB", "SES", "TOR", "VPN"}, false),
+					ValidateFunc: validateIPListSource(ruleTypeProxyType),
+				},
+			},
+			"asn": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: ipListConflicts("asn"),
+				Description:   "Autonomous system numbers, e.g. `13335` or `AS13335`.",
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateFunc:     validateASN,
+					DiffSuppressFunc: suppressASNDiff,
 				},
 			},
 			"application": {
@@ -84,6 +120,25 @@ func resourceWallarmIPList(listType wallarm.IPListType) *schema.Resource {
 				Optional: true,
 				Default:  "Terraform managed IP list",
 			},
+			"renew": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      ipListRenewNever,
+				ValidateFunc: validation.StringInSlice(ipListRenewPolicies, false),
+				Description: "When to recompute the expiry: `never` (keep the expiry set at creation), " +
+					"`on_change` (recompute when the entries are rebuilt and re-add expired entries), " +
+					"`always` (recompute on every apply).",
+			},
+			"expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Absolute expiry of the entries (RFC3339, UTC).",
+			},
+			"expired": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "True once expires_at has passed and the Wallarm Cloud dropped the entries.",
+			},
 			"entry_count": {
 				Type:        schema.TypeInt,
 				Computed:    true,
@@ -143,21 +198,12 @@ func resourceWallarmIPListCreate(listType wallarm.IPListType) schema.CreateConte
 			return diags
 		}
 		if len(rules) == 0 {
-			return diag.FromErr(fmt.Errorf("at least one of ip_range, country, datacenter, or proxy_type must be specified"))
+			return diag.FromErr(fmt.Errorf("at least one of ip_range, country, datacenter, proxy_type, or asn must be specified"))
 		}
 
-		var apps []int
-		if v, ok := d.GetOk("application"); ok {
-			applications := v.([]any)
-			apps = make([]int, len(applications))
-			for i := range applications {
-				apps[i] = applications[i].(int)
-			}
-		} else {
-			apps = []int{0} // 0 means all applications
-		}
+		apps := ipListApplicationIDs(d)
 
-		unixTime, diags := parseExpireTime(d)
+		unixTime, diags := resolveIPListExpiry(d, time.Now())
 		if diags != nil {
 			return diags
 		}
@@ -210,6 +256,8 @@ func resourceWallarmIPListCreate(listType wallarm.IPListType) schema.CreateConte
 			return diag.FromErr(err)
 		}
 		d.Set("client_id", clientID)
+		d.Set("expires_at", formatExpiresAt(unixTime))
+		d.Set("expired", false)
 
 		if len(missing) > 0 {
 			log.Printf("[WARN] IP list Create: %d values not found in API after retries", len(missing))
@@ -241,10 +289,24 @@ func resourceWallarmIPListRead(listType wallarm.IPListType) schema.ReadContextFu
 		}
 
 		found, missing := cache.LookupMany(listType, configValues)
+		now := time.Now()
 
 		if len(found) == 0 {
 			if !d.IsNewResource() {
 				oldAddrs := d.Get("address_id").([]any)
+				if isExpired(d.Get("expires_at").(string), now) {
+					// The Cloud drops entries once expired_at passes. Keep the resource
+					// in state so the renew policy decides whether to re-add it.
+					log.Printf("[INFO] IP list %s expired at %s", d.Id(), d.Get("expires_at").(string))
+					d.Set("expired", true)
+					d.Set("address_id", []any{})
+					d.Set("entry_count", 0)
+					d.Set("untracked_count", len(configValues))
+					if err := d.Set("untracked_ips", configValues); err != nil {
+						return diag.FromErr(fmt.Errorf("cannot set untracked_ips: %v", err))
+					}
+					return nil
+				}
 				if len(oldAddrs) > 0 {
 					log.Printf("[WARN] IP list %s was previously tracked but no longer found — removing from state", d.Id())
 					d.SetId("")
@@ -266,10 +328,26 @@ func resourceWallarmIPListRead(listType wallarm.IPListType) schema.ReadContextFu
 		}
 		d.Set("client_id", clientID)
 
-		return nil
+		expiresAt := formatExpiresAt(earliestExpiredAt(found))
+		d.Set("expires_at", expiresAt)
+		d.Set("expired", isExpired(expiresAt, now))
+
+		return ipListExpiryWarning(d.Id(), expiresAt, d.Get("renew").(string), now)
 	}
 }
 
+// earliestExpiredAt returns the soonest non-forever expired_at among entries,
+// or ipListNoExpiry when none of them expire.
+func earliestExpiredAt(entries []IPCacheEntry) int {
+	earliest := ipListNoExpiry
+	for _, e := range entries {
+		if e.ExpiredAt != ipListNoExpiry && (earliest == ipListNoExpiry || e.ExpiredAt < earliest) {
+			earliest = e.ExpiredAt
+		}
+	}
+	return earliest
+}
+
 func resourceWallarmIPListUpdate(listType wallarm.IPListType) schema.UpdateContextFunc {
 	return func(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
 		client := apiClient(m)
@@ -280,8 +358,13 @@ func resourceWallarmIPListUpdate(listType wallarm.IPListType) schema.UpdateConte
 
 		cache := m.(*ProviderMeta).IPListCache
 
+		// A renew policy change only affects future plans.
+		if !d.HasChangesExcept("renew") {
+			return nil
+		}
+
 		// If only ip_range changed (subnet type), do a targeted diff update.
-		if d.HasChange("ip_range") && !d.HasChanges("time_format", "time", "reason", "application") {
+		if d.HasChange("ip_range") && !d.HasChanges("time_format", "time", "reason", "application", "expires_at") {
 			return ipListSubnetDiffUpdate(ctx, d, m, client, clientID, listType, cache)
 		}
 
@@ -364,19 +447,12 @@ func ipListSubnetDiffUpdate(
 
 	// Create added IPs.
 	if len(added) > 0 {
-		unixTime, diags := parseExpireTime(d)
+		unixTime, diags := resolveIPListExpiry(d, time.Now())
 		if diags != nil {
 			return diags
 		}
 
-		var apps []int
-		if v, ok := d.GetOk("application"); ok {
-			for _, a := range v.([]any) {
-				apps = append(apps, a.(int))
-			}
-		} else {
-			apps = []int{0}
-		}
+		apps := ipListApplicationIDs(d)
 
 		params := wallarm.AccessRuleCreateRequest{
 			List:           listType,
@@ -404,6 +480,20 @@ func ipListSubnetDiffUpdate(
 	return nil
 }
 
+// ipListApplicationIDs returns the configured application IDs, or [0] (all applications).
+func ipListApplicationIDs(d *schema.ResourceData) []int {
+	v, ok := d.GetOk("application")
+	if !ok {
+		return []int{0} // 0 means all applications
+	}
+	applications := v.([]any)
+	apps := make([]int, len(applications))
+	for i := range applications {
+		apps[i] = applications[i].(int)
+	}
+	return apps
+}
+
 // deleteByAddrIDs deletes IP list entries using group IDs from the address_id state.
 func deleteByAddrIDs(client wallarm.API, clientID int, addrIDs []any) diag.Diagnostics {
 	ruleTypeIDs := make(map[string][]int)
@@ -432,19 +522,30 @@ func deleteByAddrIDs(client wallarm.API, clientID int, addrIDs []any) diag.Diagn
 	return nil
 }
 
-// ipListConfigValues extracts all config values (ip_range, country, datacenter, proxy_type) from schema.
+// ipListConfigValues extracts all config values (see ipListSourceFields) from schema,
+// in the form the API stores them.
 func ipListConfigValues(d *schema.ResourceData) []string {
 	var values []string
-	for _, field := range []string{"ip_range", "country", "datacenter", "proxy_type"} {
-		if v, ok := d.GetOk(field); ok {
+	for _, f := range ipListSourceFields {
+		if v, ok := d.GetOk(f.field); ok {
 			for _, item := range v.([]any) {
-				values = append(values, item.(string))
+				values = append(values, ipListAPIValue(f.ruleType, item.(string)))
 			}
 		}
 	}
 	return values
 }
 
+// ipListAPIValue normalizes a config value to the form the API stores.
+func ipListAPIValue(ruleType, value string) string {
+	if ruleType == ruleTypeASN {
+		if asn, err := normalizeASN(value); err == nil {
+			return asn
+		}
+	}
+	return value
+}
+
 // cacheEntriesToAddrIDs converts cache entries to the address_id schema format, sorted by group ID.
 func cacheEntriesToAddrIDs(entries []IPCacheEntry) []any {
 	// Sort by GroupID for deterministic ordering.
@@ -595,7 +696,7 @@ func resourceWallarmIPListImport(listType wallarm.IPListType) schema.StateContex
 		d.Set("client_id", clientID)
 
 		// Fetch all groups for this list type.
-		allGroups, err := client.IPListRead(listType, clientID, IPListPageSize)
+		allGroups, err := client.IPListReadByRuleType(listType, clientID, allRuleTypes, IPListPageSize)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read IP lists: %w", err)
 		}
@@ -741,8 +842,7 @@ func resourceWallarmIPListImport(listType wallarm.IPListType) schema.StateContex
 
 			d.Set("ip_range", ips)
 			d.Set("reason", reason)
-			d.Set("time_format", "RFC3339")
-			d.Set("time", time.Unix(int64(expiredAt), 0).UTC().Format(time.RFC3339))
+			setImportedExpiry(d, expiredAt)
 			if len(apps) > 0 {
 				d.Set("application", apps)
 			}
@@ -783,8 +883,7 @@ func resourceWallarmIPListImport(listType wallarm.IPListType) schema.StateContex
 		d.Set("address_id", addrIDs)
 		d.Set("entry_count", len(addrIDs))
 		d.Set("reason", found.Reason)
-		d.Set("time_format", "RFC3339")
-		d.Set("time", time.Unix(int64(found.ExpiredAt), 0).UTC().Format(time.RFC3339))
+		setImportedExpiry(d, found.ExpiredAt)
 		if len(found.ApplicationIDs) > 0 {
 			d.Set("application", found.ApplicationIDs)
 		}
@@ -796,12 +895,12 @@ func resourceWallarmIPListImport(listType wallarm.IPListType) schema.StateContex
 				ips[i] = strings.TrimSuffix(v, "/32")
 			}
 			d.Set("ip_range", ips)
-		case "location":
-			d.Set("country", found.Values)
-		case "datacenter":
-			d.Set("datacenter", found.Values)
-		case "proxy_type":
-			d.Set("proxy_type", found.Values)
+		default:
+			for _, f := range ipListSourceFields {
+				if f.ruleType == found.RuleType {
+					d.Set(f.field, found.Values)
+				}
+			}
 		}
 
 		rules, _ := buildRulesFromSchema(d)
@@ -817,71 +916,31 @@ func resourceWallarmIPListImport(listType wallarm.IPListType) schema.StateContex
 func buildRulesFromSchema(d *schema.ResourceData) ([]wallarm.AccessRuleEntry, diag.Diagnostics) {
 	var rules []wallarm.AccessRuleEntry
 
-	// subnet rules from ip_range
-	if v, ok := d.GetOk("ip_range"); ok {
-		ipRange := v.([]any)
-		var ips []string
-		for _, v := range ipRange {
-			ip := v.(string)
-			if strings.Contains(ip, "/") {
-				subNetwork, err := strconv.Atoi(strings.Split(ip, "/")[1])
-				if err != nil {
-					return nil, diag.FromErr(fmt.Errorf("cannot parse subnet to integer. must be the number, got %v", err))
+	for _, f := range ipListSourceFields {
+		v, ok := d.GetOk(f.field)
+		if !ok {
+			continue
+		}
+		var vals []string
+		for _, item := range v.([]any) {
+			val := item.(string)
+			switch f.ruleType {
+			case ruleTypeSubnet:
+				if err := validateIPListSubnet(val); err != nil {
+					return nil, diag.FromErr(err)
 				}
-				if subNetwork < 8 {
-					return nil, diag.FromErr(fmt.Errorf("subnet must be >= /8, got %v", subNetwork))
+			case ruleTypeASN:
+				asn, err := normalizeASN(val)
+				if err != nil {
+					return nil, diag.FromErr(err)
 				}
+				val = asn
 			}
-			ips = append(ips, ip)
-		}
-		if len(ips) > 0 {
-			rules = append(rules, wallarm.AccessRuleEntry{
-				RulesType: ruleTypeSubnet,
-				Values:    ips,
-			})
-		}
-	}
-
-	// location rules from country
-	if v, ok := d.GetOk("country"); ok {
-		countries := v.([]any)
-		var vals []string
-		for _, c := range countries {
-			vals = append(vals, c.(string))
-		}
-		if len(vals) > 0 {
-			rules = append(rules, wallarm.AccessRuleEntry{
-				RulesType: "location",
-				Values:    vals,
-			})
-		}
-	}
-
-	// datacenter rules from datacenter
-	if v, ok := d.GetOk("datacenter"); ok {
-		sources := v.([]any)
-		var vals []string
-		for _, s := range sources {
-			vals = append(vals, s.(string))
-		}
-		if len(vals) > 0 {
-			rules = append(rules, wallarm.AccessRuleEntry{
-				RulesType: "datacenter",
-				Values:    vals,
-			})
-		}
-	}
-
-	// proxy_type rules from proxy_type
-	if v, ok := d.GetOk("proxy_type"); ok {
-		proxies := v.([]any)
-		var vals []string
-		for _, p := range proxies {
-			vals = append(vals, p.(string))
+			vals = append(vals, val)
 		}
 		if len(vals) > 0 {
 			rules = append(rules, wallarm.AccessRuleEntry{
-				RulesType: "proxy_type",
+				RulesType: f.ruleType,
 				Values:    vals,
 			})
 		}
@@ -890,65 +949,31 @@ func buildRulesFromSchema(d *schema.ResourceData) ([]wallarm.AccessRuleEntry, di
 	return rules, nil
 }
 
-func parseExpireTime(d *schema.ResourceData) (int, diag.Diagnostics) {
-	timeFormat := strings.ToLower(d.Get("time_format").(string))
+// validateIPListSubnet rejects subnets wider than /8.
+func validateIPListSubnet(ip string) error {
+	if !strings.Contains(ip, "/") {
+		return nil
+	}
+	subNetwork, err := strconv.Atoi(strings.Split(ip, "/")[1])
+	if err != nil {
+		return fmt.Errorf("cannot parse subnet to integer. must be the number, got %v", err)
+	}
+	if subNetwork < 8 {
+		return fmt.Errorf("subnet must be >= /8, got %v", subNetwork)
+	}
+	return nil
+}
 
-	switch timeFormat {
-	case "forever":
-		return int(time.Now().AddDate(100, 0, 0).Unix()), nil
-	case "minutes":
-		expireTime, err := strconv.Atoi(d.Get("time").(string))
-		if err != nil {
-			return 0, diag.FromErr(fmt.Errorf("cannot parse time to integer. must be the number when `time_format` equals `Minutes`, got %v", err))
-		}
-		if expireTime == 0 {
-			expireTime = 60045120
-		}
-		return int(time.Now().Add(time.Minute * time.Duration(expireTime)).Unix()), nil
-	case "hours":
-		expireTime, err := strconv.Atoi(d.Get("time").(string))
-		if err != nil {
-			return 0, diag.FromErr(fmt.Errorf("cannot parse time to integer. must be the number when `time_format` equals `Hours`, got %v", err))
-		}
-		if expireTime == 0 {
-			expireTime = 60045120
-		}
-		return int(time.Now().Add(time.Hour * time.Duration(expireTime)).Unix()), nil
-	case "days":
-		expireTime, err := strconv.Atoi(d.Get("time").(string))
-		if err != nil {
-			return 0, diag.FromErr(fmt.Errorf("cannot parse time to integer. must be the number when `time_format` equals `Days`, got %v", err))
-		}
-		if expireTime == 0 {
-			expireTime = 60045120
-		}
-		return int(time.Now().Add(24 * time.Hour * time.Duration(expireTime)).Unix()), nil
-	case "weeks":
-		expireTime, err := strconv.Atoi(d.Get("time").(string))
-		if err != nil {
-			return 0, diag.FromErr(fmt.Errorf("cannot parse time to integer. must be the number when `time_format` equals `Weeks`, got %v", err))
-		}
-		if expireTime == 0 {
-			expireTime = 60045120
-		}
-		return int(time.Now().Add(7 * 24 * time.Hour * time.Duration(expireTime)).Unix()), nil
-	case "months":
-		expireTime, err := strconv.Atoi(d.Get("time").(string))
-		if err != nil {
-			return 0, diag.FromErr(fmt.Errorf("cannot parse time to integer. must be the number when `time_format` equals `Months`, got %v", err))
-		}
-		if expireTime == 0 {
-			expireTime = 60045120
-		}
-		return int(time.Now().AddDate(0, expireTime, 0).Unix()), nil
-	case "rfc3339":
-		expireTime, err := time.Parse(time.RFC3339, d.Get("time").(string))
-		if err != nil {
-			return 0, diag.FromErr(fmt.Errorf("cannot parse time to integer. must be the valid RFC3339 time when `time_format` equals `RFC3339`, got %v.\nExample: 2006-01-02T15:04:05+07:00", err))
-		}
-		return int(expireTime.Unix()), nil
+// setImportedExpiry records an API expired_at as an absolute expiry on import.
+func setImportedExpiry(d *schema.ResourceData, expiredAt int) {
+	if expiredAt == ipListNoExpiry {
+		d.Set("time_format", "Forever")
+	} else {
+		d.Set("time_format", "RFC3339")
+		d.Set("time", formatExpiresAt(expiredAt))
 	}
-	return 0, diag.FromErr(fmt.Errorf("unsupported time_format"))
+	d.Set("expires_at", formatExpiresAt(expiredAt))
+	d.Set("expired", false)
 }
 
 // ipListFriendlyType maps API list type values to user-facing names for resource IDs.
@@ -969,10 +994,11 @@ func ipListFriendlyType(listType wallarm.IPListType) string {
 func ipListRuleTypes(rules []wallarm.AccessRuleEntry) string {
 	// Map API rule type names to user-facing names.
 	friendly := map[string]string{
-		ruleTypeSubnet: ruleTypeSubnet,
-		"location":     "country",
-		"datacenter":   "datacenter",
-		"proxy_type":   "proxy",
+		ruleTypeSubnet:     ruleTypeSubnet,
+		ruleTypeLocation:   "country",
+		ruleTypeDatacenter: "datacenter",
+		ruleTypeProxyType:  "proxy",
+		ruleTypeASN:        "asn",
 	}
 	var types []string
 	for _, r := range rules {
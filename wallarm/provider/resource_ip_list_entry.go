@@ -0,0 +1,404 @@
+package wallarm
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wallarm/wallarm-go"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ipListEntryRuleTypes maps the user-facing rule_type of wallarm_ip_list_entry
+// to the API rule type.
+var ipListEntryRuleTypes = map[string]string{
+	ruleTypeSubnet: ruleTypeSubnet,
+	"country":      ruleTypeLocation,
+	"datacenter":   ruleTypeDatacenter,
+	"proxy_type":   ruleTypeProxyType,
+	"asn":          ruleTypeASN,
+}
+
+// resourceWallarmIPListEntry manages exactly one IP list group holding a single
+// value. It shares IPListCache and the per-list-type Create lock with the bulk
+// wallarm_allowlist/denylist/graylist resources and only ever touches its own group.
+func resourceWallarmIPListEntry() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages a single value in the allowlist, denylist or graylist.",
+
+		CreateContext: resourceWallarmIPListEntryCreate,
+		ReadContext:   resourceWallarmIPListEntryRead,
+		UpdateContext: resourceWallarmIPListEntryUpdate,
+		DeleteContext: resourceWallarmIPListEntryDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceWallarmIPListEntryImport,
+		},
+		CustomizeDiff: customdiff.All(ipListEntryValidateValue, ipListExpiryCustomizeDiff),
+
+		Schema: map[string]*schema.Schema{
+			"client_id": defaultClientIDWithValidationSchema,
+			"list_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"allowlist", "denylist", "graylist"}, false),
+				Description:  "IP list type: allowlist, denylist, or graylist.",
+			},
+			"rule_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      ruleTypeSubnet,
+				ValidateFunc: validation.StringInSlice([]string{ruleTypeSubnet, "country", "datacenter", "proxy_type", "asn"}, false),
+				Description:  "Kind of value: subnet (IP or CIDR), country, datacenter, proxy_type, or asn.",
+			},
+			"value": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return d.Get("rule_type").(string) == "asn" && suppressASNDiff(k, old, new, d)
+				},
+				Description: "The IP/CIDR, country code, datacenter, proxy type or ASN.",
+			},
+			"application": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"time_format": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"Minutes", "RFC3339", "Hours", "Days", "Weeks", "Months", "Forever"}, true),
+			},
+			"time": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"reason": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "Terraform managed IP list",
+			},
+			"renew": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      ipListRenewNever,
+				ValidateFunc: validation.StringInSlice(ipListRenewPolicies, false),
+				Description:  "When to recompute the expiry: `never`, `on_change` or `always`.",
+			},
+			"expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Absolute expiry of the entry (RFC3339, UTC).",
+			},
+			"expired": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "True once expires_at has passed and the Wallarm Cloud dropped the entry.",
+			},
+			"group_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "API group ID of the entry.",
+			},
+		},
+	}
+}
+
+// ipListEntryValidateValue checks the value against its rule_type at plan time.
+func ipListEntryValidateValue(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	if !d.NewValueKnown("value") || !d.NewValueKnown("rule_type") {
+		return nil
+	}
+	value := d.Get("value").(string)
+	switch ruleType := d.Get("rule_type").(string); ruleType {
+	case ruleTypeSubnet:
+		return validateIPListSubnet(value)
+	case "asn":
+		_, err := normalizeASN(value)
+		return err
+	case "datacenter", "proxy_type":
+		return checkIPListSource(ipListEntryRuleTypes[ruleType], value)
+	}
+	return nil
+}
+
+func resourceWallarmIPListEntryCreate(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := apiClient(m)
+	clientID, err := retrieveClientID(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	listTypeStr := d.Get("list_type").(string)
+	listType := mapListType(listTypeStr)
+	ruleType := ipListEntryRuleTypes[d.Get("rule_type").(string)]
+	value := ipListAPIValue(ruleType, d.Get("value").(string))
+
+	cache := m.(*ProviderMeta).IPListCache
+	cache.LockCreate(listType)
+	defer cache.UnlockCreate(listType)
+
+	// Refuse to take over a value someone else already put on the list —
+	// deleting this resource later would remove their entry.
+	if d.IsNewResource() {
+		groupID, err := ipListEntryExistingGroup(client, cache, listType, clientID, ruleType, value)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if groupID != 0 {
+			return diag.FromErr(fmt.Errorf("%s %q already exists in the %s (group %d); import it with "+
+				"`terraform import wallarm_ip_list_entry.<name> %s`",
+				d.Get("rule_type").(string), value, listTypeStr, groupID, ipListEntryID(clientID, listTypeStr, value)))
+		}
+	}
+
+	expiredAt, diags := resolveIPListExpiry(d, time.Now())
+	if diags != nil {
+		return diags
+	}
+
+	params := wallarm.AccessRuleCreateRequest{
+		List:           listType,
+		Force:          false,
+		Reason:         d.Get("reason").(string),
+		ApplicationIDs: ipListApplicationIDs(d),
+		ExpiredAt:      expiredAt,
+		Rules:          []wallarm.AccessRuleEntry{{RulesType: ruleType, Values: []string{value}}},
+	}
+
+	if err := client.IPListCreate(clientID, params); err != nil {
+		var apiErr *wallarm.APIError
+		if stderrors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+			return diag.FromErr(fmt.Errorf("IP list entry %q conflicts with existing entries. "+
+				"Resolve the conflicts and retry.\nAPI response: %s", value, apiErr.Body))
+		}
+		return diag.FromErr(err)
+	}
+
+	d.SetId(ipListEntryID(clientID, listTypeStr, value))
+
+	found, _ := cache.RefreshUntilFound(
+		client, listType, clientID, []string{value}, []string{ruleType},
+		IPListCacheMaxRetries, IPListCacheRetryDelay*time.Second,
+	)
+	if len(found) > 0 {
+		d.Set("group_id", found[0].GroupID)
+	} else {
+		log.Printf("[WARN] IP list entry Create: %q not found in API after retries", value)
+	}
+	d.Set("client_id", clientID)
+	d.Set("expires_at", formatExpiresAt(expiredAt))
+	d.Set("expired", false)
+
+	return nil
+}
+
+func resourceWallarmIPListEntryRead(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := apiClient(m)
+	clientID, err := retrieveClientID(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	listType := mapListType(d.Get("list_type").(string))
+	value := ipListAPIValue(ipListEntryRuleTypes[d.Get("rule_type").(string)], d.Get("value").(string))
+
+	cache := m.(*ProviderMeta).IPListCache
+	if err := cache.EnsureLoaded(client, listType, clientID); err != nil {
+		return diag.FromErr(err)
+	}
+
+	now := time.Now()
+	entry, ok := cache.Lookup(listType, value)
+	if !ok {
+		switch {
+		case isExpired(d.Get("expires_at").(string), now):
+			log.Printf("[INFO] IP list entry %s expired at %s", d.Id(), d.Get("expires_at").(string))
+			d.Set("expired", true)
+			d.Set("group_id", 0)
+		case d.Get("group_id").(int) != 0:
+			log.Printf("[WARN] IP list entry %s was previously tracked but no longer found — removing from state", d.Id())
+			d.SetId("")
+		default:
+			log.Printf("[WARN] IP list entry %s not yet visible in API (group_id empty), keeping in state", d.Id())
+		}
+		return nil
+	}
+
+	d.Set("group_id", entry.GroupID)
+	d.Set("reason", entry.Reason)
+	if appIDsKey(entry.ApplicationIDs) == "all" {
+		d.Set("application", nil)
+	} else {
+		d.Set("application", entry.ApplicationIDs)
+	}
+	d.Set("client_id", clientID)
+
+	expiresAt := formatExpiresAt(entry.ExpiredAt)
+	d.Set("expires_at", expiresAt)
+	d.Set("expired", isExpired(expiresAt, now))
+
+	return ipListExpiryWarning(d.Id(), expiresAt, d.Get("renew").(string), now)
+}
+
+func resourceWallarmIPListEntryUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	// A renew policy change only affects future plans.
+	if !d.HasChangesExcept("renew") {
+		return nil
+	}
+
+	client := apiClient(m)
+	clientID, err := retrieveClientID(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Access rules have no update endpoint: rebuild the group.
+	listType := mapListType(d.Get("list_type").(string))
+	if diags := ipListEntryDeleteGroup(client, clientID, d); diags != nil {
+		return diags
+	}
+	m.(*ProviderMeta).IPListCache.Invalidate(listType)
+	return resourceWallarmIPListEntryCreate(ctx, d, m)
+}
+
+func resourceWallarmIPListEntryDelete(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := apiClient(m)
+	clientID, err := retrieveClientID(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if diags := ipListEntryDeleteGroup(client, clientID, d); diags != nil {
+		return diags
+	}
+	m.(*ProviderMeta).IPListCache.Invalidate(mapListType(d.Get("list_type").(string)))
+	return nil
+}
+
+// ipListEntryExistingGroup returns the ID of the group already holding value, or 0.
+// The shared cache is consulted first; a miss falls back to a targeted search so
+// entries added since the cache was loaded are not overlooked.
+func ipListEntryExistingGroup(client wallarm.API, cache *IPListCache, listType wallarm.IPListType, clientID int, ruleType, value string) (int, error) {
+	if err := cache.EnsureLoaded(client, listType, clientID); err != nil {
+		return 0, err
+	}
+	if entry, ok := cache.Lookup(listType, value); ok {
+		return entry.GroupID, nil
+	}
+	groups, err := client.IPListSearch(listType, clientID, ruleType, value)
+	if err != nil {
+		return 0, err
+	}
+	for _, g := range groups {
+		for _, v := range g.Values {
+			if v == value || strings.TrimSuffix(v, "/32") == value {
+				return g.ID, nil
+			}
+		}
+	}
+	return 0, nil
+}
+
+// ipListEntryDeleteGroup deletes the group recorded in group_id, if any.
+func ipListEntryDeleteGroup(client wallarm.API, clientID int, d *schema.ResourceData) diag.Diagnostics {
+	groupID := d.Get("group_id").(int)
+	if groupID == 0 {
+		return nil
+	}
+	ruleType := ipListEntryRuleTypes[d.Get("rule_type").(string)]
+	if err := client.IPListDelete(clientID, []wallarm.AccessRuleDeleteEntry{
+		{RuleType: ruleType, IDs: []int{groupID}},
+	}); err != nil {
+		if isNotFoundError(err) {
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// ipListEntryID builds the resource ID {clientID}/{list_type}/{value}.
+func ipListEntryID(clientID int, listType, value string) string {
+	return fmt.Sprintf("%d/%s/%s", clientID, listType, value)
+}
+
+// resourceWallarmIPListEntryImport imports a single value.
+//
+// Import ID format:
+//
+//	{clientID}/{list_type}/{value}
+//
+// Examples:
+//
+//	terraform import wallarm_ip_list_entry.office 8649/allowlist/203.0.113.0/24
+//	terraform import wallarm_ip_list_entry.tor 8649/denylist/TOR
+//
+// Only groups holding exactly this value can be imported; groups created in
+// bulk belong to wallarm_allowlist/denylist/graylist.
+func resourceWallarmIPListEntryImport(_ context.Context, d *schema.ResourceData, m any) ([]*schema.ResourceData, error) {
+	client := apiClient(m)
+
+	parts := strings.SplitN(d.Id(), "/", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected {clientID}/{list_type}/{value}", d.Id())
+	}
+	clientID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid client_id %q: %w", parts[0], err)
+	}
+	listTypeStr, value := parts[1], parts[2]
+	if !slices.Contains([]string{"allowlist", "denylist", "graylist"}, listTypeStr) {
+		return nil, fmt.Errorf("invalid list_type %q, expected allowlist, denylist, or graylist", listTypeStr)
+	}
+	listType := mapListType(listTypeStr)
+
+	cache := m.(*ProviderMeta).IPListCache
+	if err := cache.EnsureLoaded(client, listType, clientID); err != nil {
+		return nil, fmt.Errorf("failed to read IP lists: %w", err)
+	}
+	entry, ok := cache.Lookup(listType, value)
+	if !ok {
+		return nil, fmt.Errorf("%q not found in the %s of client %d", value, listTypeStr, clientID)
+	}
+	if entry.ValueCount > 1 {
+		return nil, fmt.Errorf("group %d holds %d values; import it with wallarm_%s using %d/%d",
+			entry.GroupID, entry.ValueCount, listTypeStr, clientID, entry.GroupID)
+	}
+
+	ruleType := ruleTypeSubnet
+	for friendly, apiType := range ipListEntryRuleTypes {
+		if apiType == entry.RuleType {
+			ruleType = friendly
+		}
+	}
+
+	d.Set("client_id", clientID)
+	d.Set("list_type", listTypeStr)
+	d.Set("rule_type", ruleType)
+	d.Set("value", value)
+	d.Set("reason", entry.Reason)
+	d.Set("renew", ipListRenewNever)
+	if appIDsKey(entry.ApplicationIDs) != "all" {
+		d.Set("application", entry.ApplicationIDs)
+	}
+	d.Set("group_id", entry.GroupID)
+	setImportedExpiry(d, entry.ExpiredAt)
+	d.SetId(ipListEntryID(clientID, listTypeStr, value))
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,187 @@
+package wallarm
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	wallarm "github.com/wallarm/wallarm-go"
+)
+
+// mockIPListAPI serves IPListReadByRuleType/IPListSearch from a fixed set of groups.
+type mockIPListAPI struct {
+	wallarm.API
+	groups []wallarm.IPRule
+}
+
+func (m *mockIPListAPI) IPListReadByRuleType(_ wallarm.IPListType, _ int, _ []string, _ int) ([]wallarm.IPRule, error) {
+	return m.groups, nil
+}
+
+func (m *mockIPListAPI) IPListSearch(_ wallarm.IPListType, _ int, _ string, query string) ([]wallarm.IPRule, error) {
+	var out []wallarm.IPRule
+	for _, g := range m.groups {
+		for _, v := range g.Values {
+			if strings.HasPrefix(v, query) {
+				out = append(out, g)
+			}
+		}
+	}
+	return out, nil
+}
+
+func TestIPListEntryImport(t *testing.T) {
+	meta := &ProviderMeta{
+		Client: &mockIPListAPI{groups: []wallarm.IPRule{
+			{ID: 10, RuleType: "subnet", Values: []string{"203.0.113.0/24"}, Reason: "office", ApplicationIDs: []int{0}, ExpiredAt: 0},
+			{ID: 11, RuleType: "location", Values: []string{"CN", "RU"}, ApplicationIDs: []int{1, 3}, ExpiredAt: 1804809600},
+		}},
+		IPListCache: NewIPListCache(),
+	}
+
+	d := resourceWallarmIPListEntry().TestResourceData()
+	d.SetId("8649/allowlist/203.0.113.0/24")
+	result, err := resourceWallarmIPListEntryImport(context.Background(), d, meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := result[0]
+	if got.Get("value").(string) != "203.0.113.0/24" || got.Get("rule_type").(string) != ruleTypeSubnet {
+		t.Errorf("value/rule_type: got %q/%q", got.Get("value"), got.Get("rule_type"))
+	}
+	if got.Get("group_id").(int) != 10 || got.Get("reason").(string) != "office" {
+		t.Errorf("group_id/reason: got %d/%q", got.Get("group_id"), got.Get("reason"))
+	}
+	if got.Get("time_format").(string) != "Forever" {
+		t.Errorf("time_format: got %q", got.Get("time_format"))
+	}
+	if len(got.Get("application").([]any)) != 0 {
+		t.Errorf("application: got %v, want empty (all)", got.Get("application"))
+	}
+}
+
+func TestIPListEntryImport_SharedGroup(t *testing.T) {
+	meta := &ProviderMeta{
+		Client: &mockIPListAPI{groups: []wallarm.IPRule{
+			{ID: 11, RuleType: "location", Values: []string{"CN", "RU"}},
+		}},
+		IPListCache: NewIPListCache(),
+	}
+
+	d := resourceWallarmIPListEntry().TestResourceData()
+	d.SetId("8649/denylist/CN")
+	_, err := resourceWallarmIPListEntryImport(context.Background(), d, meta)
+	if err == nil || !strings.Contains(err.Error(), "holds 2 values") {
+		t.Errorf("expected shared-group error, got %v", err)
+	}
+}
+
+func TestIPListEntryImport_InvalidID(t *testing.T) {
+	for _, id := range []string{"", "8649", "8649/denylist", "8649/denylist/", "abc/denylist/1.1.1.1", "8649/blocklist/1.1.1.1"} {
+		d := resourceWallarmIPListEntry().TestResourceData()
+		d.SetId(id)
+		if _, err := resourceWallarmIPListEntryImport(context.Background(), d, &ProviderMeta{}); err == nil {
+			t.Errorf("id=%q: expected error", id)
+		}
+	}
+}
+
+func TestIPListEntryExistingGroup_SearchFallback(t *testing.T) {
+	api := &mockIPListAPI{}
+	cache := NewIPListCache()
+	// Loaded while empty; the value appears afterwards and is found by search.
+	if err := cache.EnsureLoaded(api, wallarm.DenylistType, 1); err != nil {
+		t.Fatal(err)
+	}
+	api.groups = []wallarm.IPRule{{ID: 42, RuleType: "subnet", Values: []string{"1.2.3.4/32"}}}
+
+	id, err := ipListEntryExistingGroup(api, cache, wallarm.DenylistType, 1, ruleTypeSubnet, "1.2.3.4")
+	if err != nil || id != 42 {
+		t.Errorf("got %d, %v; want 42", id, err)
+	}
+	id, err = ipListEntryExistingGroup(api, cache, wallarm.DenylistType, 1, ruleTypeSubnet, "5.6.7.8")
+	if err != nil || id != 0 {
+		t.Errorf("got %d, %v; want 0", id, err)
+	}
+}
+
+func TestAccWallarmIPListEntry_Subnet(t *testing.T) {
+	rnd := generateRandomResourceName(10)
+	name := "wallarm_ip_list_entry." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testWallarmIPListEntry(rnd, "denylist", "subnet", "198.51.100.7", "tf-test-"+rnd),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "reason", "tf-test-"+rnd),
+					resource.TestCheckResourceAttrSet(name, "group_id"),
+					resource.TestCheckResourceAttrSet(name, "expires_at"),
+				),
+			},
+			{
+				Config: testWallarmIPListEntry(rnd, "denylist", "subnet", "198.51.100.7", "tf-test-updated-"+rnd),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "reason", "tf-test-updated-"+rnd),
+				),
+			},
+			{
+				ResourceName:            name,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"time", "time_format"},
+			},
+		},
+	})
+}
+
+func TestAccWallarmIPListEntry_Country(t *testing.T) {
+	rnd := generateRandomResourceName(10)
+	name := "wallarm_ip_list_entry." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testWallarmIPListEntry(rnd, "graylist", "country", "KP", "tf-test-"+rnd),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "value", "KP"),
+					resource.TestCheckResourceAttrSet(name, "group_id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWallarmIPListEntry_InvalidDatacenter(t *testing.T) {
+	rnd := generateRandomResourceName(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testWallarmIPListEntry(rnd, "denylist", "datacenter", "nowhere", "tf-test-"+rnd),
+				ExpectError: regexp.MustCompile(`is not a valid datacenter`),
+			},
+		},
+	})
+}
+
+func testWallarmIPListEntry(resourceID, listType, ruleType, value, reason string) string {
+	return fmt.Sprintf(`
+resource "wallarm_ip_list_entry" "%[1]s" {
+	list_type   = "%[2]s"
+	rule_type   = "%[3]s"
+	value       = "%[4]s"
+	reason      = "%[5]s"
+	time_format = "Days"
+	time        = 1
+}`, resourceID, listType, ruleType, value, reason)
+}
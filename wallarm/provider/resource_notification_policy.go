@@ -0,0 +1,378 @@
+package wallarm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/wallarm/wallarm-go"
+)
+
+// resourceWallarmNotificationPolicy manages one trigger per template from a
+// shared set of filters, integrations and IP actions. The trigger IDs are
+// tracked in the computed triggers map (template_id -> trigger_id).
+func resourceWallarmNotificationPolicy() *schema.Resource {
+	triggerSchema := resourceWallarmTriggerSchema(triggerThresholdSchema)
+	return &schema.Resource{
+		CreateContext: resourceWallarmNotificationPolicyCreate,
+		ReadContext:   resourceWallarmNotificationPolicyRead,
+		UpdateContext: resourceWallarmNotificationPolicyUpdate,
+		DeleteContext: resourceWallarmNotificationPolicyDelete,
+		CustomizeDiff: customdiff.All(
+			notificationPolicyValidateDiff,
+			notificationPolicyMissingTriggersDiff,
+		),
+
+		Schema: map[string]*schema.Schema{
+			"client_id": defaultClientIDWithValidationSchema,
+
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Policy name. Each trigger is named \"<name> (<template_id>)\".",
+			},
+
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			"templates": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(triggerTemplateIDs(), false),
+				},
+			},
+
+			"filters": triggerSchema["filters"],
+
+			"integration_ids": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "Integrations notified by the send_notification action of every template that has it.",
+			},
+
+			"block_ips":       notificationPolicyLockActionSchema(),
+			"add_to_graylist": notificationPolicyLockActionSchema(),
+
+			"threshold": triggerThresholdSchema,
+
+			"triggers": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "Trigger ID of each template.",
+			},
+		},
+	}
+}
+
+func notificationPolicyLockActionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"lock_time": {
+					Type:         schema.TypeInt,
+					Required:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"lock_time_format": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "Seconds",
+					ValidateFunc: validation.StringInSlice([]string{"Seconds", "Minutes", "Hours", "Days", "Weeks", "Months"}, false),
+				},
+			},
+		},
+	}
+}
+
+// policyConfigGetter is implemented by both *schema.ResourceData and
+// *schema.ResourceDiff.
+type policyConfigGetter interface {
+	Get(key string) any
+}
+
+// policyTrigger is the trigger configuration of one template, in the shape of
+// the wallarm_trigger schema.
+type policyTrigger struct {
+	TemplateID string
+	Filters    []any
+	Actions    []any
+	Threshold  []any
+}
+
+// notificationPolicyTriggers expands the policy into one trigger per template.
+// Every template gets the shared filters, the actions it supports and, if it
+// counts events, the threshold. A template that supports none of the
+// configured actions is an error.
+func notificationPolicyTriggers(d policyConfigGetter) ([]policyTrigger, error) {
+	var actions []any
+	if ids := d.Get("integration_ids").(*schema.Set).List(); len(ids) > 0 {
+		slices.SortFunc(ids, func(a, b any) int { return a.(int) - b.(int) })
+		actions = append(actions, map[string]any{"action_id": "send_notification", "integration_id": ids})
+	}
+	for _, actionID := range triggerLockActions {
+		if cfg := d.Get(actionID).([]any); len(cfg) > 0 && cfg[0] != nil {
+			m := cfg[0].(map[string]any)
+			actions = append(actions, map[string]any{
+				"action_id":        actionID,
+				"lock_time":        m["lock_time"],
+				"lock_time_format": m["lock_time_format"],
+			})
+		}
+	}
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("at least one of integration_ids, block_ips or add_to_graylist must be set")
+	}
+
+	templates := make([]string, 0)
+	for _, t := range d.Get("templates").(*schema.Set).List() {
+		templates = append(templates, t.(string))
+	}
+	slices.Sort(templates)
+
+	filters := d.Get("filters").([]any)
+	threshold := d.Get("threshold").([]any)
+	result := make([]policyTrigger, 0, len(templates))
+	for _, templateID := range templates {
+		tpl, ok := triggerTemplates[templateID]
+		if !ok {
+			return nil, fmt.Errorf("unknown template %q", templateID)
+		}
+		t := policyTrigger{TemplateID: templateID, Filters: filters}
+		for _, a := range actions {
+			if tpl.acceptsAction(a.(map[string]any)["action_id"].(string)) {
+				t.Actions = append(t.Actions, a)
+			}
+		}
+		if len(t.Actions) == 0 {
+			return nil, fmt.Errorf("template %q supports none of the configured actions, allowed actions: %s",
+				templateID, triggerAllowedList(tpl.Actions))
+		}
+		if tpl.Threshold != triggerThresholdRejected {
+			t.Threshold = threshold
+		}
+		if err := validateTriggerConfig(templateID, tpl, t.Filters, t.Actions, t.Threshold); err != nil {
+			return nil, fmt.Errorf("template %q: %w", templateID, err)
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+func notificationPolicyValidateDiff(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	for _, k := range []string{"templates", "filters", "integration_ids", "block_ips", "add_to_graylist", "threshold"} {
+		if !d.NewValueKnown(k) {
+			return nil
+		}
+	}
+	_, err := notificationPolicyTriggers(d)
+	return err
+}
+
+// notificationPolicyMissingTriggersDiff plans an update when a template has no
+// trigger, e.g. after the trigger was deleted in Console.
+func notificationPolicyMissingTriggersDiff(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	if d.Id() == "" || !d.NewValueKnown("templates") {
+		return nil
+	}
+	triggers := d.Get("triggers").(map[string]any)
+	for _, t := range d.Get("templates").(*schema.Set).List() {
+		if _, ok := triggers[t.(string)]; !ok {
+			return d.SetNewComputed("triggers")
+		}
+	}
+	return nil
+}
+
+// notificationPolicyTriggerBody builds the trigger create/update body with the
+// same expanders as wallarm_trigger.
+func notificationPolicyTriggerBody(d *schema.ResourceData, t policyTrigger) (*wallarm.TriggerCreate, error) {
+	filters, err := expandWallarmTriggerFilter(t.Filters)
+	if err != nil {
+		return nil, err
+	}
+	comment := d.Get("comment").(string)
+	if comment == "" {
+		comment = "This trigger set by Terraform"
+	}
+	param := &wallarm.TriggerParam{
+		Name:       fmt.Sprintf("%s (%s)", d.Get("name").(string), t.TemplateID),
+		Comment:    comment,
+		TemplateID: t.TemplateID,
+		Enabled:    d.Get("enabled").(bool),
+		Filters:    filters,
+		Actions:    expandWallarmTriggerAction(t.Actions),
+	}
+	if len(t.Threshold) > 0 {
+		if param.Threshold, err = expandWallarmTriggerThreshold(t.Threshold); err != nil {
+			return nil, err
+		}
+	}
+	return &wallarm.TriggerCreate{Trigger: param}, nil
+}
+
+func resourceWallarmNotificationPolicyCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	return resourceWallarmNotificationPolicyApply(ctx, d, m, schema.TimeoutCreate)
+}
+
+func resourceWallarmNotificationPolicyUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	return resourceWallarmNotificationPolicyApply(ctx, d, m, schema.TimeoutUpdate)
+}
+
+// resourceWallarmNotificationPolicyApply reconciles the triggers with the
+// policy: it creates triggers for new templates, updates the others and
+// deletes the triggers of removed templates. The triggers map is saved even
+// when a call fails, so the next apply continues from there.
+func resourceWallarmNotificationPolicyApply(ctx context.Context, d *schema.ResourceData, m any, timeout string) diag.Diagnostics {
+	client := apiClient(m)
+	clientID, err := retrieveClientID(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	desired, err := notificationPolicyTriggers(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%d/%s", clientID, d.Get("name").(string)))
+
+	triggers := make(map[string]any)
+	for k, v := range d.Get("triggers").(map[string]any) {
+		triggers[k] = v
+	}
+	save := func(err error) diag.Diagnostics {
+		d.Set("triggers", triggers)
+		return diag.FromErr(err)
+	}
+
+	var created []int
+	for _, t := range desired {
+		body, err := notificationPolicyTriggerBody(d, t)
+		if err != nil {
+			return save(err)
+		}
+		if id, ok := triggers[t.TemplateID]; ok {
+			if _, err := client.TriggerUpdate(body, clientID, id.(int)); err != nil {
+				if !isNotFoundError(err) {
+					return save(fmt.Errorf("template %q: failed to update trigger %d: %w", t.TemplateID, id, err))
+				}
+				delete(triggers, t.TemplateID)
+			} else {
+				continue
+			}
+		}
+		resp, err := client.TriggerCreate(body, clientID)
+		if err != nil {
+			return save(fmt.Errorf("template %q: failed to create trigger: %w", t.TemplateID, err))
+		}
+		triggers[t.TemplateID] = resp.ID
+		created = append(created, resp.ID)
+	}
+
+	for templateID, id := range triggers {
+		if slices.ContainsFunc(desired, func(t policyTrigger) bool { return t.TemplateID == templateID }) {
+			continue
+		}
+		if err := client.TriggerDelete(clientID, id.(int)); err != nil && !isNotFoundError(err) {
+			return save(fmt.Errorf("template %q: failed to delete trigger %d: %w", templateID, id, err))
+		}
+		delete(triggers, templateID)
+	}
+	d.Set("triggers", triggers)
+
+	if err := waitForTriggers(ctx, client, clientID, created, d.Timeout(timeout)); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceWallarmNotificationPolicyRead(ctx, d, m)
+}
+
+// waitForTriggers waits until the triggers list returns every ID, so that the
+// read after create does not drop them.
+func waitForTriggers(ctx context.Context, client wallarm.API, clientID int, ids []int, timeout time.Duration) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		resp, err := client.TriggerRead(clientID)
+		if err != nil {
+			return retry.NonRetryableError(err)
+		}
+		for _, id := range ids {
+			if !slices.ContainsFunc(resp.Triggers, func(t wallarm.TriggerResp) bool { return t.ID == id }) {
+				return retry.RetryableError(fmt.Errorf("can't find a trigger with ID: %d", id))
+			}
+		}
+		return nil
+	})
+}
+
+// resourceWallarmNotificationPolicyRead drops triggers that no longer exist
+// from the triggers map; notificationPolicyMissingTriggersDiff then plans
+// their re-creation. Console edits of the remaining triggers are not
+// detected and are overwritten by the next apply.
+func resourceWallarmNotificationPolicyRead(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := apiClient(m)
+	clientID, err := retrieveClientID(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resp, err := client.TriggerRead(clientID)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to read triggers for client %d: %w", clientID, err))
+	}
+
+	triggers := make(map[string]any)
+	for templateID, id := range d.Get("triggers").(map[string]any) {
+		if slices.ContainsFunc(resp.Triggers, func(t wallarm.TriggerResp) bool { return t.ID == id.(int) }) {
+			triggers[templateID] = id
+		}
+	}
+	d.Set("client_id", clientID)
+	if err := d.Set("triggers", triggers); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceWallarmNotificationPolicyDelete(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := apiClient(m)
+	clientID, err := retrieveClientID(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	triggers := d.Get("triggers").(map[string]any)
+	templates := make([]string, 0, len(triggers))
+	for templateID := range triggers {
+		templates = append(templates, templateID)
+	}
+	slices.Sort(templates)
+	for _, templateID := range templates {
+		id := triggers[templateID].(int)
+		if err := client.TriggerDelete(clientID, id); err != nil && !isNotFoundError(err) {
+			return diag.FromErr(fmt.Errorf("template %q: failed to delete trigger %d: %w", templateID, id, err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package wallarm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	wallarm "github.com/wallarm/wallarm-go"
+)
+
+// mockPolicyTriggerAPI keeps triggers in memory.
+type mockPolicyTriggerAPI struct {
+	wallarm.API
+	nextID   int
+	triggers map[int]*wallarm.TriggerParam
+	deleted  []int
+}
+
+func newMockPolicyTriggerAPI() *mockPolicyTriggerAPI {
+	return &mockPolicyTriggerAPI{nextID: 100, triggers: map[int]*wallarm.TriggerParam{}}
+}
+
+func (m *mockPolicyTriggerAPI) TriggerCreate(body *wallarm.TriggerCreate, _ int) (*wallarm.TriggerCreateResp, error) {
+	m.nextID++
+	m.triggers[m.nextID] = body.Trigger
+	return &wallarm.TriggerCreateResp{TriggerResp: &wallarm.TriggerResp{ID: m.nextID}}, nil
+}
+
+func (m *mockPolicyTriggerAPI) TriggerUpdate(body *wallarm.TriggerCreate, _, id int) (*wallarm.TriggerCreateResp, error) {
+	if _, ok := m.triggers[id]; !ok {
+		return nil, wallarm.NewAPIError(404, "not found")
+	}
+	m.triggers[id] = body.Trigger
+	return &wallarm.TriggerCreateResp{TriggerResp: &wallarm.TriggerResp{ID: id}}, nil
+}
+
+func (m *mockPolicyTriggerAPI) TriggerDelete(_, id int) error {
+	delete(m.triggers, id)
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func (m *mockPolicyTriggerAPI) TriggerRead(_ int) (*wallarm.TriggerRead, error) {
+	var resp wallarm.TriggerRead
+	for id, t := range m.triggers {
+		r := wallarm.TriggerResp{ID: id, Name: t.Name, Enabled: t.Enabled}
+		r.Template.ID = t.TemplateID
+		resp.Triggers = append(resp.Triggers, r)
+	}
+	return &resp, nil
+}
+
+func testPolicyResourceData(templates ...any) *schema.ResourceData {
+	d := resourceWallarmNotificationPolicy().TestResourceData()
+	d.Set("name", "critical")
+	d.Set("enabled", true)
+	d.Set("templates", templates)
+	d.Set("integration_ids", []any{2, 1})
+	d.Set("block_ips", []any{map[string]any{"lock_time": 1, "lock_time_format": "Hours"}})
+	d.Set("threshold", []any{map[string]any{"count": 10, "period": 60, "time_format": "Seconds", "operator": "gt"}})
+	return d
+}
+
+func TestNotificationPolicyTriggers(t *testing.T) {
+	d := testPolicyResourceData("attacks_exceeded", "user_created", "vector_attack")
+	triggers, err := notificationPolicyTriggers(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actionIDs := func(p policyTrigger) []string {
+		var ids []string
+		for _, a := range p.Actions {
+			ids = append(ids, a.(map[string]any)["action_id"].(string))
+		}
+		return ids
+	}
+	want := map[string]struct {
+		actions   []string
+		threshold bool
+	}{
+		"attacks_exceeded": {[]string{"send_notification", "block_ips"}, true},
+		"user_created":     {[]string{"send_notification"}, false},
+		"vector_attack":    {[]string{"send_notification", "block_ips"}, true},
+	}
+	if len(triggers) != len(want) {
+		t.Fatalf("got %d triggers, want %d", len(triggers), len(want))
+	}
+	for _, p := range triggers {
+		w := want[p.TemplateID]
+		if got := actionIDs(p); !reflect.DeepEqual(got, w.actions) {
+			t.Errorf("%s actions: got %v, want %v", p.TemplateID, got, w.actions)
+		}
+		if (len(p.Threshold) > 0) != w.threshold {
+			t.Errorf("%s threshold: got %v", p.TemplateID, p.Threshold)
+		}
+	}
+	if ids := triggers[0].Actions[0].(map[string]any)["integration_id"]; !reflect.DeepEqual(ids, []any{1, 2}) {
+		t.Errorf("integration_id: got %v", ids)
+	}
+
+	for _, tc := range []struct {
+		templates []any
+		filters   []any
+		err       string
+	}{
+		{
+			templates: []any{"attacks_exceeded", "api_structure_changed"},
+			filters:   []any{map[string]any{"filter_id": "change_type", "operator": "eq", "value": []any{"added"}}},
+			err:       `template "attacks_exceeded": filters.0: filter "change_type" is not supported`,
+		},
+	} {
+		d := testPolicyResourceData(tc.templates...)
+		d.Set("filters", tc.filters)
+		if _, err := notificationPolicyTriggers(d); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%v: got %v, want %q", tc.templates, err, tc.err)
+		}
+	}
+
+	d = testPolicyResourceData("user_created")
+	d.Set("integration_ids", []any{})
+	if _, err := notificationPolicyTriggers(d); err == nil || !strings.Contains(err.Error(), `template "user_created" supports none of the configured actions`) {
+		t.Errorf("user_created with block_ips only: got %v", err)
+	}
+	d.Set("block_ips", []any{})
+	if _, err := notificationPolicyTriggers(d); err == nil {
+		t.Error("a policy without actions must be rejected")
+	}
+}
+
+func TestNotificationPolicy_Reconcile(t *testing.T) {
+	client := newMockPolicyTriggerAPI()
+	meta := &ProviderMeta{Client: client, DefaultClientID: 1}
+	r := resourceWallarmNotificationPolicy()
+
+	d := testPolicyResourceData("attacks_exceeded", "user_created")
+	if diags := r.CreateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	if d.Id() != "1/critical" || len(client.triggers) != 2 {
+		t.Fatalf("create: id=%q triggers=%v", d.Id(), client.triggers)
+	}
+	triggers := d.Get("triggers").(map[string]any)
+	userCreated := triggers["user_created"].(int)
+	if got := client.triggers[userCreated]; got.Name != "critical (user_created)" || got.Threshold != nil {
+		t.Errorf("user_created trigger: got %+v", got)
+	}
+	attacks := triggers["attacks_exceeded"].(int)
+	if got := client.triggers[attacks].Threshold; got == nil || got.Count != 10 || got.Period != 60 {
+		t.Errorf("attacks_exceeded threshold: got %+v", got)
+	}
+
+	// Replace user_created with vector_attack; attacks_exceeded is updated in place.
+	d.Set("templates", []any{"attacks_exceeded", "vector_attack"})
+	d.Set("enabled", false)
+	if diags := r.UpdateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+	triggers = d.Get("triggers").(map[string]any)
+	if !reflect.DeepEqual(client.deleted, []int{userCreated}) || triggers["attacks_exceeded"] != attacks || len(triggers) != 2 {
+		t.Errorf("update: deleted=%v triggers=%v", client.deleted, triggers)
+	}
+	if client.triggers[attacks].Enabled {
+		t.Error("update: attacks_exceeded must be disabled")
+	}
+
+	// A trigger deleted outside Terraform is dropped on read and recreated.
+	delete(client.triggers, attacks)
+	if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	if _, ok := d.Get("triggers").(map[string]any)["attacks_exceeded"]; ok {
+		t.Fatal("read: the deleted trigger must be dropped")
+	}
+	if diags := r.UpdateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+	if len(client.triggers) != 2 {
+		t.Errorf("update: the deleted trigger must be recreated, got %v", client.triggers)
+	}
+
+	if diags := r.DeleteContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+	if len(client.triggers) != 0 {
+		t.Errorf("delete: triggers left %v", client.triggers)
+	}
+}
+
+func TestAccWallarmNotificationPolicy(t *testing.T) {
+	rnd := generateRandomResourceName(10)
+	name := "wallarm_notification_policy." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWallarmNotificationPolicy(rnd, `"attacks_exceeded", "user_created"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "triggers.%", "2"),
+					resource.TestCheckResourceAttrSet(name, "triggers.attacks_exceeded"),
+					resource.TestCheckResourceAttrSet(name, "triggers.user_created"),
+				),
+			},
+			{
+				Config: testAccWallarmNotificationPolicy(rnd, `"attacks_exceeded", "vector_attack"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "triggers.%", "2"),
+					resource.TestCheckResourceAttrSet(name, "triggers.vector_attack"),
+					resource.TestCheckNoResourceAttr(name, "triggers.user_created"),
+				),
+			},
+		},
+	})
+}
+
+func testAccWallarmNotificationPolicy(resourceID, templates string) string {
+	return fmt.Sprintf(`
+resource "wallarm_integration_email" "%[1]s" {
+	emails = ["%[1]s@wallarm.com"]
+
+	event {
+		event_type = "system"
+		active = true
+	}
+}
+
+resource "wallarm_notification_policy" "%[1]s" {
+	name = "tf-test-%[1]s"
+	templates = [%[2]s]
+	integration_ids = [wallarm_integration_email.%[1]s.integration_id]
+
+	block_ips {
+		lock_time = 1
+		lock_time_format = "Hours"
+	}
+
+	threshold {
+		count = 10000
+		period = 86400
+	}
+}`, resourceID, templates)
+}
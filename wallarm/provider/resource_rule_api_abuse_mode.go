@@ -15,6 +15,9 @@ import (
 
 const ruleTypeAPIAbuseMode = "api_abuse_mode"
 
+// resourceWallarmAPIAbuseModeUpdate is shared by Update and the on_conflict = "adopt" path of Create.
+var resourceWallarmAPIAbuseModeUpdate = resourcerule.Update(apiClient, resourcerule.WithMode)
+
 func resourceWallarmAPIAbuseMode() *schema.Resource {
 	fields := map[string]*schema.Schema{
 		"mode": {
@@ -24,12 +27,13 @@ func resourceWallarmAPIAbuseMode() *schema.Resource {
 			ValidateFunc: validation.StringInSlice([]string{"enabled", "disabled"}, false),
 			Description:  "API abuse mode. One of: enabled, disabled. Default: enabled. Mutable in-place via Update; removing the line from HCL restores the default.",
 		},
-		"action": resourcerule.ScopeActionSchema(),
+		"action":      resourcerule.ScopeActionSchema(),
+		"on_conflict": onConflictSchema,
 	}
 	return &schema.Resource{
 		CreateContext: resourceWallarmAPIAbuseModeCreate,
 		ReadContext:   resourceWallarmAPIAbuseModeRead,
-		UpdateContext: resourcerule.Update(apiClient, resourcerule.WithMode),
+		UpdateContext: resourceWallarmAPIAbuseModeUpdate,
 		DeleteContext: resourcerule.Delete(apiClient),
 		Importer: &schema.ResourceImporter{
 			StateContext: resourcerule.Import(ruleTypeAPIAbuseMode),
@@ -40,9 +44,13 @@ func resourceWallarmAPIAbuseMode() *schema.Resource {
 }
 
 func resourceWallarmAPIAbuseModeCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	if diags := guardExistingHint(d, m, ruleTypeAPIAbuseMode, "wallarm_rule_api_abuse_mode", nil); diags.HasError() {
+	adopted, diags := guardExistingHint(ctx, d, m, ruleTypeAPIAbuseMode, "wallarm_rule_api_abuse_mode", nil, resourceWallarmAPIAbuseModeUpdate)
+	if diags.HasError() {
 		return diags
 	}
+	if adopted {
+		return append(diags, resourceWallarmAPIAbuseModeRead(ctx, d, m)...)
+	}
 
 	client := apiClient(m)
 	clientID, err := retrieveClientID(d, m)
@@ -77,7 +85,7 @@ func resourceWallarmAPIAbuseModeCreate(ctx context.Context, d *schema.ResourceDa
 	d.Set("action_id", resp.Body.ActionID)
 	d.Set("rule_type", ruleTypeAPIAbuseMode)
 	d.SetId(fmt.Sprintf("%d/%d/%d", clientID, resp.Body.ActionID, resp.Body.ID))
-	return resourceWallarmAPIAbuseModeRead(ctx, d, m)
+	return append(diags, resourceWallarmAPIAbuseModeRead(ctx, d, m)...)
 }
 
 func resourceWallarmAPIAbuseModeRead(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
@@ -15,6 +15,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// resourceWallarmModeUpdate is shared by Update and the on_conflict = "adopt" path of Create.
+var resourceWallarmModeUpdate = resourcerule.Update(apiClient, resourcerule.WithMode)
+
 func resourceWallarmMode() *schema.Resource {
 	fields := map[string]*schema.Schema{
 		"mode": {
@@ -23,12 +26,13 @@ func resourceWallarmMode() *schema.Resource {
 			ValidateFunc: validation.StringInSlice([]string{"default", "off", "monitoring", "block", "safe_blocking"}, false),
 		},
 
-		"action": resourcerule.ScopeActionSchema(),
+		"action":      resourcerule.ScopeActionSchema(),
+		"on_conflict": onConflictSchema,
 	}
 	return &schema.Resource{
 		CreateContext: resourceWallarmModeCreate,
 		ReadContext:   resourceWallarmModeRead,
-		UpdateContext: resourcerule.Update(apiClient, resourcerule.WithMode),
+		UpdateContext: resourceWallarmModeUpdate,
 		DeleteContext: resourcerule.Delete(apiClient),
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceWallarmModeImport,
@@ -39,12 +43,16 @@ func resourceWallarmMode() *schema.Resource {
 }
 
 func resourceWallarmModeCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	if diags := guardExistingHint(d, m, "wallarm_mode", "wallarm_rule_mode",
+	adopted, diags := guardExistingHint(ctx, d, m, "wallarm_mode", "wallarm_rule_mode",
 		func(c, a int, r *wallarm.ActionBody) string {
 			return fmt.Sprintf("%d/%d/%d/%s", c, a, r.ID, r.Mode)
-		}); diags.HasError() {
+		}, resourceWallarmModeUpdate)
+	if diags.HasError() {
 		return diags
 	}
+	if adopted {
+		return append(diags, resourceWallarmModeRead(ctx, d, m)...)
+	}
 	client := apiClient(m)
 	clientID, err := retrieveClientID(d, m)
 	if err != nil {
@@ -83,7 +91,7 @@ func resourceWallarmModeCreate(ctx context.Context, d *schema.ResourceData, m an
 	resID := fmt.Sprintf("%d/%d/%d/%s", clientID, actionResp.Body.ActionID, actionResp.Body.ID, actionResp.Body.Mode)
 	d.SetId(resID)
 
-	return resourceWallarmModeRead(ctx, d, m)
+	return append(diags, resourceWallarmModeRead(ctx, d, m)...)
 }
 
 func resourceWallarmModeRead(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
@@ -13,9 +13,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// resourceWallarmOverlimitResSettingsUpdate is shared by Update and the on_conflict = "adopt" path of Create.
+var resourceWallarmOverlimitResSettingsUpdate = resourcerule.Update(apiClient, resourcerule.WithMode, resourcerule.WithOverlimitTime)
+
 func resourceWallarmOverlimitResSettings() *schema.Resource {
 	fields := map[string]*schema.Schema{
-		"action": resourcerule.ScopeActionSchema(),
+		"action":      resourcerule.ScopeActionSchema(),
+		"on_conflict": onConflictSchema,
 
 		"overlimit_time": {
 			Type:         schema.TypeInt,
@@ -37,7 +41,7 @@ func resourceWallarmOverlimitResSettings() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceWallarmOverlimitResSettingsCreate,
 		ReadContext:   resourceWallarmOverlimitResSettingsRead,
-		UpdateContext: resourcerule.Update(apiClient, resourcerule.WithMode, resourcerule.WithOverlimitTime),
+		UpdateContext: resourceWallarmOverlimitResSettingsUpdate,
 		DeleteContext: resourcerule.Delete(apiClient),
 		Importer: &schema.ResourceImporter{
 			StateContext: resourcerule.Import("overlimit_res_settings"),
@@ -48,9 +52,13 @@ func resourceWallarmOverlimitResSettings() *schema.Resource {
 }
 
 func resourceWallarmOverlimitResSettingsCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	if diags := guardExistingHint(d, m, "overlimit_res_settings", "wallarm_rule_overlimit_res_settings", nil); diags.HasError() {
+	adopted, diags := guardExistingHint(ctx, d, m, "overlimit_res_settings", "wallarm_rule_overlimit_res_settings", nil, resourceWallarmOverlimitResSettingsUpdate)
+	if diags.HasError() {
 		return diags
 	}
+	if adopted {
+		return append(diags, resourceWallarmOverlimitResSettingsRead(ctx, d, m)...)
+	}
 
 	client := apiClient(m)
 	clientID, err := retrieveClientID(d, m)
@@ -97,7 +105,7 @@ func resourceWallarmOverlimitResSettingsCreate(ctx context.Context, d *schema.Re
 	resID := fmt.Sprintf("%d/%d/%d", clientID, actionID, actionResp.Body.ID)
 	d.SetId(resID)
 
-	return resourceWallarmOverlimitResSettingsRead(ctx, d, m)
+	return append(diags, resourceWallarmOverlimitResSettingsRead(ctx, d, m)...)
 }
 
 func resourceWallarmOverlimitResSettingsRead(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
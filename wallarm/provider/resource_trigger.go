@@ -3,6 +3,8 @@ package wallarm
 import (
 	"context"
 	"fmt"
+	"math"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -24,116 +26,198 @@ func resourceWallarmTrigger() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceWallarmTriggerImport,
 		},
-
-		Schema: map[string]*schema.Schema{
-			"client_id": defaultClientIDWithValidationSchema,
-
-			"template_id": {
-				Type:     schema.TypeString,
-				Required: true,
-				ValidateFunc: validation.StringInSlice([]string{"user_created", "attacks_exceeded",
-					"hits_exceeded", "incidents_exceeded", "vector_attack", "forced_browsing_started", "bruteforce_started", "bola_search_started",
-					"blacklist_ip_added", "api_structure_changed", "attack_ip_grouping", "compromised_logins",
-					"rogue_api_detected"}, false),
+		CustomizeDiff: triggerTemplateCustomizeDiff,
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceWallarmTriggerV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceWallarmTriggerStateUpgradeV0,
 			},
+		},
 
-			"enabled": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  true,
-			},
+		Schema: resourceWallarmTriggerSchema(triggerThresholdSchema),
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(1 * time.Second),
+		},
+	}
+}
 
-			"name": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "Terraform managed trigger",
-			},
+// resourceWallarmTriggerSchema returns the trigger schema with the given
+// threshold attribute, shared by the current and the v0 (map threshold) schema.
+func resourceWallarmTriggerSchema(threshold *schema.Schema) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"client_id": defaultClientIDWithValidationSchema,
 
-			"comment": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-			},
+		"template_id": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice(triggerTemplateIDs(), false),
+		},
+
+		"enabled": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+
+		"name": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "Terraform managed trigger",
+		},
 
-			"filters": {
-				Type:     schema.TypeList,
-				Optional: true,
-				MaxItems: 6,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"filter_id": {
-							Type:     schema.TypeString,
-							Optional: true,
-							ValidateFunc: validation.StringInSlice([]string{"ip_address", "pool", "attack_type",
-								"domain", "target", "response_status", "url", "hint_tag", "pii", "change_type", "deviation_type",
-								"api_spec_ids"}, false),
-						},
-
-						"operator": {
-							Type:         schema.TypeString,
-							Optional:     true,
-							ValidateFunc: validation.StringInSlice([]string{"eq", "ne"}, false),
-						},
-
-						"value": {
-							Type:     schema.TypeList,
-							Optional: true,
-							Elem:     &schema.Schema{Type: schema.TypeString},
-						},
+		"comment": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+
+		"filters": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 6,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"filter_id": {
+						Type:     schema.TypeString,
+						Optional: true,
+						ValidateFunc: validation.StringInSlice([]string{"ip_address", "pool", "attack_type",
+							"domain", "target", "response_status", "url", "hint_tag", "pii", "change_type", "deviation_type",
+							"api_spec_ids"}, false),
 					},
-				},
-			},
 
-			"actions": {
-				Type:     schema.TypeList,
-				Required: true,
-				MaxItems: 4,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"action_id": {
-							Type:         schema.TypeString,
-							Required:     true,
-							ValidateFunc: validation.StringInSlice([]string{"send_notification", "block_ips", "mark_as_brute", "group_attack_by_ip", "add_to_graylist"}, false),
-						},
-
-						"integration_id": {
-							Type:     schema.TypeList,
-							Optional: true,
-							Elem:     &schema.Schema{Type: schema.TypeInt},
-						},
-
-						"lock_time": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							Computed: true,
-						},
-						"lock_time_format": {
-							Type:         schema.TypeString,
-							Optional:     true,
-							Computed:     true,
-							ValidateFunc: validation.StringInSlice([]string{"Seconds", "Minutes", "Hours", "Days", "Weeks", "Months"}, false),
-						},
+					"operator": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						ValidateFunc: validation.StringInSlice([]string{"eq", "ne"}, false),
+					},
+
+					"value": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
 					},
 				},
 			},
+		},
+
+		"actions": {
+			Type:     schema.TypeList,
+			Required: true,
+			MaxItems: 4,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"action_id": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringInSlice([]string{"send_notification", "block_ips", "mark_as_brute", "group_attack_by_ip", "add_to_graylist"}, false),
+					},
+
+					"integration_id": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeInt},
+					},
 
-			"threshold": {
-				Type:     schema.TypeMap,
-				Optional: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
+					"lock_time": {
+						Type:     schema.TypeInt,
+						Optional: true,
+						Computed: true,
+					},
+					"lock_time_format": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Computed:     true,
+						ValidateFunc: validation.StringInSlice([]string{"Seconds", "Minutes", "Hours", "Days", "Weeks", "Months"}, false),
+					},
 				},
 			},
+		},
 
-			"trigger_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
+		"threshold": threshold,
+
+		"trigger_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
 		},
-		Timeouts: &schema.ResourceTimeout{
-			Read: schema.DefaultTimeout(1 * time.Second),
+	}
+}
+
+// triggerThresholdSchema is the typed threshold block. Which templates require
+// or reject it, and the accepted operators, are checked by
+// triggerTemplateCustomizeDiff.
+var triggerThresholdSchema = &schema.Schema{
+	Type:     schema.TypeList,
+	Optional: true,
+	MaxItems: 1,
+	Elem: &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"count": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"period": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"time_format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "Seconds",
+				ValidateFunc: validation.StringInSlice([]string{"Seconds", "Minutes"}, false),
+			},
+			"operator": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "gt",
+				ValidateFunc: validation.StringInSlice([]string{"gt"}, false),
+			},
 		},
+	},
+}
+
+// resourceWallarmTriggerV0 is the schema before threshold became a block; it
+// was a map of strings (period, time_format, operator, count).
+func resourceWallarmTriggerV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: resourceWallarmTriggerSchema(&schema.Schema{
+			Type:     schema.TypeMap,
+			Optional: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		}),
+	}
+}
+
+// resourceWallarmTriggerStateUpgradeV0 converts the v0 threshold map into the
+// typed threshold block.
+func resourceWallarmTriggerStateUpgradeV0(_ context.Context, rawState map[string]any, _ any) (map[string]any, error) {
+	old, _ := rawState["threshold"].(map[string]any)
+	if len(old) == 0 {
+		rawState["threshold"] = []any{}
+		return rawState, nil
+	}
+
+	block := map[string]any{"time_format": "Seconds", "operator": "gt"}
+	for _, key := range []string{"count", "period"} {
+		v, _ := old[key].(string)
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("upgrading threshold.%s %q: %w", key, v, err)
+		}
+		block[key] = n
 	}
+	if v, _ := old["time_format"].(string); v != "" {
+		block["time_format"] = v
+	}
+	if v, _ := old["operator"].(string); v != "" {
+		block["operator"] = v
+	}
+	rawState["threshold"] = []any{block}
+	return rawState, nil
 }
 
 func resourceWallarmTriggerCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
@@ -155,15 +239,6 @@ func resourceWallarmTriggerCreate(ctx context.Context, d *schema.ResourceData, m
 	templateID := d.Get("template_id").(string)
 	enabled := d.Get("enabled").(bool)
 
-	switch templateID {
-	case "attacks_exceeded",
-		"hits_exceeded", "incidents_exceeded",
-		"vector_attack", "bruteforce_started":
-		if _, ok := d.GetOk("threshold"); !ok {
-			return diag.FromErr(fmt.Errorf(`"threshold" must be presented with the "%s" template`, templateID))
-		}
-	}
-
 	filters, err := expandWallarmTriggerFilter(d.Get("filters"))
 	if err != nil {
 		return diag.FromErr(err)
@@ -242,23 +317,25 @@ func resourceWallarmTriggerCreate(ctx context.Context, d *schema.ResourceData, m
 	return resourceWallarmTriggerRead(ctx, d, m)
 }
 
-func resourceWallarmTriggerRead(_ context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := apiClient(m)
+func resourceWallarmTriggerRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
 	clientID, err := retrieveClientID(d, m)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	triggerID := d.Get("trigger_id").(int)
 
-	triggers, err := client.TriggerRead(clientID)
+	triggers, err := readTriggers(ctx, m, clientID)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("failed to read triggers for client %d: %w", clientID, err))
 	}
 
-	for _, t := range triggers.Triggers {
+	for _, t := range triggers {
 		if t.ID == triggerID {
 			d.Set("trigger_id", t.ID)
 			d.Set("client_id", clientID)
+			if err := setTriggerFields(d, &t); err != nil {
+				return diag.FromErr(err)
+			}
 			return nil
 		}
 	}
@@ -266,6 +343,55 @@ func resourceWallarmTriggerRead(_ context.Context, d *schema.ResourceData, m any
 	return nil
 }
 
+// apiTrigger is a trigger of the denormalized triggers list together with the
+// action params (integration_ids, lock_time) wallarm.TriggerResp drops.
+type apiTrigger struct {
+	wallarm.TriggerResp
+	Actions []wallarm.TriggerActions `json:"actions"`
+}
+
+// readTriggers lists the triggers of a client with their action params.
+func readTriggers(ctx context.Context, m any, clientID int) ([]apiTrigger, error) {
+	raw, err := apiRaw(m)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Triggers []apiTrigger `json:"triggers"`
+	}
+	uri := fmt.Sprintf("/v2/clients/%d/triggers?denormalize=true", clientID)
+	if err := raw.do(ctx, http.MethodGet, uri, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Triggers, nil
+}
+
+// setTriggerFields flattens an API trigger into d.
+func setTriggerFields(d *schema.ResourceData, t *apiTrigger) error {
+	if t.Template.ID != "" {
+		if err := d.Set("template_id", t.Template.ID); err != nil {
+			return err
+		}
+	}
+	if err := d.Set("enabled", t.Enabled); err != nil {
+		return err
+	}
+	if err := d.Set("name", t.Name); err != nil {
+		return err
+	}
+	comment, _ := t.Comment.(string)
+	if err := d.Set("comment", comment); err != nil {
+		return err
+	}
+	if err := d.Set("filters", flattenWallarmTriggerFilters(t.Filters)); err != nil {
+		return err
+	}
+	if err := d.Set("actions", flattenWallarmTriggerActions(t.Actions, d.Get("actions").([]any))); err != nil {
+		return err
+	}
+	return d.Set("threshold", flattenWallarmTriggerThreshold(&t.TriggerResp, d.Get("threshold").([]any)))
+}
+
 func resourceWallarmTriggerUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
 	var (
 		err         error
@@ -504,7 +630,7 @@ func expandWallarmTriggerAction(d any) *[]wallarm.TriggerActions {
 				lockTimeInt = int(shiftTime.Sub(currTime).Seconds())
 			}
 			if lockTimeInt == 0 {
-				lockTimeInt = 315360000 // forever, our max time
+				lockTimeInt = triggerLockTimeForever
 			}
 			a.Params.LockTime = lockTimeInt
 		}
@@ -516,40 +642,164 @@ func expandWallarmTriggerAction(d any) *[]wallarm.TriggerActions {
 }
 
 func expandWallarmTriggerThreshold(cfg any) (*wallarm.TriggerThreshold, error) {
-	threshold := wallarm.TriggerThreshold{}
-	m := cfg.(map[string]any)
+	threshold := wallarm.TriggerThreshold{AllowedOperators: []string{"gt"}}
+	blocks := cfg.([]any)
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil, fmt.Errorf("threshold block is empty")
+	}
+	m := blocks[0].(map[string]any)
 
-	period, ok := m["period"]
-	if ok {
-		periodInt, err := strconv.Atoi(period.(string))
-		if err != nil {
-			return nil, err
+	threshold.Period = m["period"].(int)
+	if m["time_format"] == "Minutes" {
+		threshold.Period *= 60
+	}
+	threshold.Operator = m["operator"].(string)
+	threshold.Count = m["count"].(int)
+
+	return &threshold, nil
+}
+
+// flattenWallarmTriggerFilters converts API filters into the filters schema.
+// Numeric values (pool, api_spec_ids, response_status codes) are rendered as
+// strings, the inverse of expandWallarmTriggerFilter.
+func flattenWallarmTriggerFilters(filters []any) []any {
+	result := make([]any, 0, len(filters))
+	for _, f := range filters {
+		m, ok := f.(map[string]any)
+		if !ok {
+			continue
+		}
+		filterID, _ := m["id"].(string)
+		operator, _ := m["operator"].(string)
+		rawValues, _ := m["values"].([]any)
+		values := make([]any, 0, len(rawValues))
+		for _, v := range rawValues {
+			values = append(values, triggerFilterValueString(v))
 		}
-		threshold.Period = periodInt
+		result = append(result, map[string]any{
+			"filter_id": filterID,
+			"operator":  operator,
+			"value":     values,
+		})
 	}
+	return result
+}
 
-	timeFormat, ok := m["time_format"]
-	if ok {
-		if timeFormat == "Minutes" {
-			threshold.Period = 60 * threshold.Period
+func triggerFilterValueString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// triggerLockTimeForever is the lock_time sent for lock_time = 0 ("forever").
+const triggerLockTimeForever = 315360000
+
+// triggerLockTimeUnits are the seconds per lock_time_format unit, except
+// Months, which expandWallarmTriggerAction resolves against the calendar.
+var triggerLockTimeUnits = map[string]int{
+	"Seconds": 1,
+	"Minutes": 60,
+	"Hours":   60 * 60,
+	"Days":    24 * 60 * 60,
+	"Weeks":   7 * 24 * 60 * 60,
+}
+
+// flattenWallarmTriggerActions converts API actions, with their
+// integration_ids and lock_time params, into the actions schema. lock_time is
+// rendered in the lock_time_format of the first unused prior action with the
+// same action_id when it divides evenly (see flattenTriggerLockTime).
+func flattenWallarmTriggerActions(actions []wallarm.TriggerActions, prior []any) []any {
+	used := make([]bool, len(prior))
+	result := make([]any, 0, len(actions))
+	for _, a := range actions {
+		var priorFormat string
+		for i, p := range prior {
+			pm, ok := p.(map[string]any)
+			if !ok || used[i] || pm["action_id"] != a.ID {
+				continue
+			}
+			used[i] = true
+			priorFormat, _ = pm["lock_time_format"].(string)
+			break
+		}
+		integrationIDs := make([]any, 0, len(a.Params.IntegrationIds))
+		for _, id := range a.Params.IntegrationIds {
+			integrationIDs = append(integrationIDs, id)
 		}
+		lockTime, lockTimeFormat := flattenTriggerLockTime(a.Params.LockTime, priorFormat)
+		result = append(result, map[string]any{
+			"action_id":        a.ID,
+			"integration_id":   integrationIDs,
+			"lock_time":        lockTime,
+			"lock_time_format": lockTimeFormat,
+		})
 	}
+	return result
+}
 
-	operator, ok := m["operator"]
-	if ok {
-		threshold.Operator = operator.(string)
+// flattenTriggerLockTime converts the API lock_time in seconds back to
+// lock_time and lock_time_format. The forever value reads as 0. The prior
+// format is kept when the seconds are a whole number of its unit (for Months,
+// within three days of that many average months); otherwise, e.g. after
+// import, the lock time is rendered in seconds.
+func flattenTriggerLockTime(seconds int, priorFormat string) (int, string) {
+	switch {
+	case seconds == 0:
+		return 0, priorFormat
+	case seconds == triggerLockTimeForever:
+		if priorFormat == "" {
+			priorFormat = "Seconds"
+		}
+		return 0, priorFormat
+	}
+	if unit, ok := triggerLockTimeUnits[priorFormat]; ok && seconds%unit == 0 {
+		return seconds / unit, priorFormat
+	}
+	if priorFormat == "Months" {
+		const month = 2629746 // average Gregorian month in seconds
+		n := int(math.Round(float64(seconds) / month))
+		if n > 0 && math.Abs(float64(seconds-n*month)) <= 3*24*60*60 {
+			return n, "Months"
+		}
 	}
+	return seconds, "Seconds"
+}
 
-	threshold.AllowedOperators = []string{"gt"}
+// flattenWallarmTriggerThreshold converts the API threshold into the
+// threshold block. The API stores the period in seconds; when the prior state
+// used time_format = "Minutes" and the period is a whole number of minutes it
+// is rendered back in minutes.
+func flattenWallarmTriggerThreshold(t *wallarm.TriggerResp, prior []any) []any {
+	count, period, operator := t.Threshold.Count, t.Threshold.Period, t.Threshold.Operator
+	if count == 0 && period == 0 && len(t.Thresholds) > 0 {
+		count, period, operator = t.Thresholds[0].Count, t.Thresholds[0].Period, t.Thresholds[0].Operator
+	}
+	if count == 0 && period == 0 {
+		return nil
+	}
+	if operator == "" {
+		operator = "gt"
+	}
 
-	count, ok := m["count"]
-	if ok {
-		countInt, err := strconv.Atoi(count.(string))
-		if err != nil {
-			return nil, err
+	timeFormat := "Seconds"
+	if len(prior) > 0 && prior[0] != nil {
+		if prior[0].(map[string]any)["time_format"] == "Minutes" && period%60 == 0 {
+			timeFormat = "Minutes"
+			period /= 60
 		}
-		threshold.Count = countInt
 	}
 
-	return &threshold, nil
+	return []any{map[string]any{
+		"count":       count,
+		"period":      period,
+		"time_format": timeFormat,
+		"operator":    operator,
+	}}
 }
@@ -1,15 +1,294 @@
 package wallarm
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	wallarm "github.com/wallarm/wallarm-go"
 )
 
+// mockTriggerAPI serves TriggerRead from a fixed JSON payload.
+type mockTriggerAPI struct {
+	wallarm.API
+	payload string
+}
+
+func (m *mockTriggerAPI) TriggerRead(_ int) (*wallarm.TriggerRead, error) {
+	var t wallarm.TriggerRead
+	if err := json.Unmarshal([]byte(m.payload), &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// testTriggerMeta returns a provider meta whose triggers list serves payload.
+func testTriggerMeta(t *testing.T, payload string) *ProviderMeta {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v2/clients/8649/triggers" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, payload)
+	}))
+	t.Cleanup(srv.Close)
+	return &ProviderMeta{RawAPI: newRawAPI(srv.Client(), srv.URL, http.Header{}, "test")}
+}
+
+const testTriggerPayload = `{"triggers": [{
+	"id": 77, "name": "Brute", "comment": null, "enabled": false, "client_id": 8649,
+	"template": {"id": "bruteforce_started"},
+	"filters": [
+		{"id": "hint_tag", "operator": "eq", "values": ["b:abc"]},
+		{"id": "pool", "operator": "ne", "values": [3, 4]},
+		{"id": "response_status", "operator": "eq", "values": ["5xx", 404]}
+	],
+	"actions": [{"id": "mark_as_brute", "params": {}}, {"id": "block_ips", "params": {"lock_time": 7200}}],
+	"threshold": {"operator": "gt", "period": 1800, "count": 30}
+}]}`
+
+func TestTriggerRead_Flatten(t *testing.T) {
+	meta := testTriggerMeta(t, testTriggerPayload)
+
+	d := resourceWallarmTrigger().TestResourceData()
+	d.SetId("8649/bruteforce_started/77")
+	d.Set("client_id", 8649)
+	d.Set("trigger_id", 77)
+	d.Set("actions", []any{
+		map[string]any{"action_id": "block_ips", "lock_time": 2, "lock_time_format": "Hours"},
+		map[string]any{"action_id": "mark_as_brute"},
+	})
+	d.Set("threshold", []any{map[string]any{"count": 30, "period": 30, "time_format": "Minutes", "operator": "gt"}})
+
+	if diags := resourceWallarmTriggerRead(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if d.Get("template_id") != "bruteforce_started" || d.Get("name") != "Brute" || d.Get("enabled") != false || d.Get("comment") != "" {
+		t.Errorf("scalars: got template_id=%v name=%v enabled=%v comment=%q",
+			d.Get("template_id"), d.Get("name"), d.Get("enabled"), d.Get("comment"))
+	}
+	wantFilters := []any{
+		map[string]any{"filter_id": "hint_tag", "operator": "eq", "value": []any{"b:abc"}},
+		map[string]any{"filter_id": "pool", "operator": "ne", "value": []any{"3", "4"}},
+		map[string]any{"filter_id": "response_status", "operator": "eq", "value": []any{"5xx", "404"}},
+	}
+	if got := d.Get("filters"); !reflect.DeepEqual(got, wantFilters) {
+		t.Errorf("filters:\n got %v\nwant %v", got, wantFilters)
+	}
+	if d.Get("actions.0.action_id") != "mark_as_brute" || d.Get("actions.1.action_id") != "block_ips" {
+		t.Errorf("actions order: got %v", d.Get("actions"))
+	}
+	if d.Get("actions.1.lock_time") != 2 || d.Get("actions.1.lock_time_format") != "Hours" {
+		t.Errorf("block_ips lock_time not read in the configured unit: got %v", d.Get("actions.1"))
+	}
+	wantThreshold := []any{map[string]any{"count": 30, "period": 30, "time_format": "Minutes", "operator": "gt"}}
+	if got := d.Get("threshold"); !reflect.DeepEqual(got, wantThreshold) {
+		t.Errorf("threshold: got %v, want %v", got, wantThreshold)
+	}
+}
+
+func TestTriggerRead_Gone(t *testing.T) {
+	meta := testTriggerMeta(t, `{"triggers": []}`)
+
+	d := resourceWallarmTrigger().TestResourceData()
+	d.SetId("8649/bruteforce_started/77")
+	d.Set("client_id", 8649)
+	d.Set("trigger_id", 77)
+	if diags := resourceWallarmTriggerRead(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the trigger to be removed from state, id=%q", d.Id())
+	}
+}
+
+func TestFlattenWallarmTriggerActions_Import(t *testing.T) {
+	var actions []wallarm.TriggerActions
+	if err := json.Unmarshal([]byte(`[
+		{"id": "send_notification", "params": {"integration_ids": [12, 34]}},
+		{"id": "block_ips", "params": {"lock_time": 7200}},
+		{"id": "add_to_graylist", "params": {"lock_time": 315360000}}
+	]`), &actions); err != nil {
+		t.Fatal(err)
+	}
+	want := []any{
+		map[string]any{"action_id": "send_notification", "integration_id": []any{12, 34}, "lock_time": 0, "lock_time_format": ""},
+		map[string]any{"action_id": "block_ips", "integration_id": []any{}, "lock_time": 7200, "lock_time_format": "Seconds"},
+		map[string]any{"action_id": "add_to_graylist", "integration_id": []any{}, "lock_time": 0, "lock_time_format": "Seconds"},
+	}
+	if got := flattenWallarmTriggerActions(actions, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestFlattenTriggerLockTime(t *testing.T) {
+	for _, tc := range []struct {
+		seconds    int
+		prior      string
+		wantTime   int
+		wantFormat string
+	}{
+		{7200, "Hours", 2, "Hours"},
+		{5400, "Hours", 5400, "Seconds"},
+		{3 * 604800, "Weeks", 3, "Weeks"},
+		{int(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC).Sub(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)).Seconds()), "Months", 3, "Months"},
+		{triggerLockTimeForever, "Days", 0, "Days"},
+		{0, "", 0, ""},
+	} {
+		gotTime, gotFormat := flattenTriggerLockTime(tc.seconds, tc.prior)
+		if gotTime != tc.wantTime || gotFormat != tc.wantFormat {
+			t.Errorf("flattenTriggerLockTime(%d, %q) = %d %q, want %d %q", tc.seconds, tc.prior, gotTime, gotFormat, tc.wantTime, tc.wantFormat)
+		}
+	}
+}
+
+func TestFlattenWallarmTriggerThreshold_Import(t *testing.T) {
+	tr := &wallarm.TriggerResp{}
+	tr.Threshold.Count, tr.Threshold.Period, tr.Threshold.Operator = 5, 3600, "gt"
+
+	want := []any{map[string]any{"count": 5, "period": 3600, "time_format": "Seconds", "operator": "gt"}}
+	if got := flattenWallarmTriggerThreshold(tr, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := flattenWallarmTriggerThreshold(&wallarm.TriggerResp{}, nil); got != nil {
+		t.Errorf("no threshold: got %v, want nil", got)
+	}
+}
+
+func TestValidateTriggerConfig(t *testing.T) {
+	notify := map[string]any{"action_id": "send_notification", "integration_id": []any{1}}
+	threshold := []any{map[string]any{"count": 5, "period": 60, "time_format": "Seconds", "operator": "gt"}}
+
+	tests := []struct {
+		name      string
+		template  string
+		filters   []any
+		actions   []any
+		threshold []any
+		wantErr   string
+	}{
+		{name: "valid notification", template: "attacks_exceeded",
+			filters: []any{map[string]any{"filter_id": "pool"}}, actions: []any{notify}, threshold: threshold},
+		{name: "valid brute", template: "bruteforce_started",
+			filters: []any{map[string]any{"filter_id": "hint_tag"}},
+			actions: []any{
+				map[string]any{"action_id": "mark_as_brute"},
+				map[string]any{"action_id": "block_ips", "lock_time": 1, "lock_time_format": "Hours"},
+			},
+			threshold: threshold},
+		{name: "block_ips on user_created", template: "user_created",
+			actions: []any{map[string]any{"action_id": "block_ips"}},
+			wantErr: `action "block_ips" is not supported by the "user_created" template, allowed actions: send_notification`},
+		{name: "api_spec_ids on bruteforce", template: "bruteforce_started",
+			filters:   []any{map[string]any{"filter_id": "api_spec_ids"}},
+			actions:   []any{map[string]any{"action_id": "mark_as_brute"}},
+			threshold: threshold,
+			wantErr:   `filter "api_spec_ids" is not supported by the "bruteforce_started" template`},
+		{name: "lock_time without format", template: "vector_attack",
+			actions:   []any{map[string]any{"action_id": "block_ips", "lock_time": 600}},
+			threshold: threshold,
+			wantErr:   `lock_time_format must be set together with lock_time`},
+		{name: "lock_time on notification", template: "attacks_exceeded",
+			actions:   []any{map[string]any{"action_id": "send_notification", "lock_time": 600, "lock_time_format": "Seconds"}},
+			threshold: threshold,
+			wantErr:   `lock_time and lock_time_format are only supported by the block_ips and add_to_graylist actions`},
+		{name: "integration_id on mark_as_brute", template: "bruteforce_started",
+			actions:   []any{map[string]any{"action_id": "mark_as_brute", "integration_id": []any{1}}},
+			threshold: threshold,
+			wantErr:   `integration_id is only supported by the send_notification action`},
+		{name: "missing threshold", template: "hits_exceeded", actions: []any{notify},
+			wantErr: `"threshold" must be presented with the "hits_exceeded" template`},
+		{name: "unexpected threshold", template: "user_created", actions: []any{notify}, threshold: threshold,
+			wantErr: `"threshold" is not supported by the "user_created" template`},
+		{name: "optional threshold set", template: "compromised_logins", actions: []any{notify}, threshold: threshold},
+		{name: "optional threshold unset", template: "bola_search_started",
+			actions: []any{map[string]any{"action_id": "mark_as_brute"}}},
+		{name: "undocumented combination", template: "attacks_exceeded",
+			filters:   []any{map[string]any{"filter_id": "hint_tag"}},
+			actions:   []any{map[string]any{"action_id": "block_ips", "lock_time": 1, "lock_time_format": "Hours"}},
+			threshold: threshold},
+		{name: "change_type outside api_structure_changed", template: "hits_exceeded",
+			filters:   []any{map[string]any{"filter_id": "change_type"}},
+			actions:   []any{notify},
+			threshold: threshold,
+			wantErr:   `filter "change_type" is not supported by the "hits_exceeded" template, allowed templates: api_structure_changed`},
+		{name: "unsupported operator", template: "hits_exceeded", actions: []any{notify},
+			threshold: []any{map[string]any{"count": 5, "period": 60, "operator": "lt"}},
+			wantErr:   `operator "lt" is not supported`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTriggerConfig(tt.template, triggerTemplates[tt.template], tt.filters, tt.actions, tt.threshold)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("got %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTriggerTemplatesCoverSchema(t *testing.T) {
+	filterIDs := resourceWallarmTrigger().Schema["filters"].Elem.(*schema.Resource).Schema["filter_id"]
+	actionIDs := resourceWallarmTrigger().Schema["actions"].Elem.(*schema.Resource).Schema["action_id"]
+	for f, templates := range triggerFilterTemplates {
+		if _, errs := filterIDs.ValidateFunc(f, "filter_id"); len(errs) > 0 {
+			t.Errorf("unknown filter %q", f)
+		}
+		for _, id := range templates {
+			if _, ok := triggerTemplates[id]; !ok {
+				t.Errorf("%s: unknown template %q", f, id)
+			}
+		}
+	}
+	for id, tpl := range triggerTemplates {
+		for _, a := range tpl.Actions {
+			if _, errs := actionIDs.ValidateFunc(a, "action_id"); len(errs) > 0 {
+				t.Errorf("%s: unknown action %q", id, a)
+			}
+		}
+	}
+}
+
+func TestResourceWallarmTriggerStateUpgradeV0(t *testing.T) {
+	got, err := resourceWallarmTriggerStateUpgradeV0(context.Background(), map[string]any{
+		"threshold": map[string]any{"count": "30", "period": "5", "time_format": "Minutes"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []any{map[string]any{"count": 30, "period": 5, "time_format": "Minutes", "operator": "gt"}}
+	if !reflect.DeepEqual(got["threshold"], want) {
+		t.Errorf("got %v, want %v", got["threshold"], want)
+	}
+
+	got, err = resourceWallarmTriggerStateUpgradeV0(context.Background(), map[string]any{}, nil)
+	if err != nil || !reflect.DeepEqual(got["threshold"], []any{}) {
+		t.Errorf("no threshold: got %v, %v", got["threshold"], err)
+	}
+
+	if _, err := resourceWallarmTriggerStateUpgradeV0(context.Background(), map[string]any{
+		"threshold": map[string]any{"count": "many", "period": "5"},
+	}, nil); err == nil {
+		t.Error("expected an error for a non-numeric count")
+	}
+}
+
 func TestAccWallarmTriggerOnlyRequiredWithError(t *testing.T) {
 	rnd := generateRandomResourceName(10)
 
@@ -57,10 +336,9 @@ func TestAccWallarmTriggerAttacksWithThreshold(t *testing.T) {
 				),
 			},
 			{
-				ResourceName: name,
-				ImportState:  true,
-				// ImportStateVerify disabled: trigger Read only populates trigger_id and client_id.
-				// Full field population from API response is a TODO.
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
 			},
 		},
 	})
@@ -125,9 +403,9 @@ func TestAccWallarmTriggerBruteforce(t *testing.T) {
 					resource.TestMatchResourceAttr(name, "filters.0.value.0", regexp.MustCompile("^b:.*")),
 					resource.TestCheckResourceAttr(name, "filters.1.filter_id", "ip_address"),
 					resource.TestCheckResourceAttr(name, "filters.1.value.0", "1.1.1.1"),
-					resource.TestCheckResourceAttr(name, "threshold.%", "3"),
-					resource.TestCheckResourceAttr(name, "threshold.count", "30"),
-					resource.TestCheckResourceAttr(name, "threshold.period", "30"),
+					resource.TestCheckResourceAttr(name, "threshold.#", "1"),
+					resource.TestCheckResourceAttr(name, "threshold.0.count", "30"),
+					resource.TestCheckResourceAttr(name, "threshold.0.period", "30"),
 				),
 			},
 		},
@@ -174,7 +452,7 @@ resource "wallarm_trigger" "%[1]s" {
 		integration_id = [%[4]s]
 	}
 
-	threshold = {
+	threshold {
 		period = 86400
 		operator = "gt"
 		count = 10000
@@ -237,7 +515,7 @@ resource "wallarm_trigger" "%[1]s" {
 		value = [504, 503]
 	}
 
-	threshold = {
+	threshold {
 		period = 86400
 		operator = "gt"
 		count = 10000
@@ -301,7 +579,7 @@ resource "wallarm_trigger" "%[1]s" {
 		value = ["5xx", "4xx"]
 	}
 
-	threshold = {
+	threshold {
 		period = 1
 		operator = "gt"
 		count = 1
@@ -351,9 +629,10 @@ resource "wallarm_trigger" "%[1]s" {
 	actions {
 		action_id = "%[4]s"
 		lock_time = 2592000
+		lock_time_format = "Seconds"
 	}
 
-	threshold = {
+	threshold {
 		period = 30
 		operator = "gt"
 		count = 30
@@ -392,3 +671,43 @@ func testAccCheckWallarmTriggerDestroy(s *terraform.State) error {
 	}
 	return nil
 }
+
+func TestFakeAPI_TriggerImportReadsActionParams(t *testing.T) {
+	srv := testFakeAPIServer(t)
+	meta := testFakeAPIMeta(t, srv, nil)
+	ctx := context.Background()
+	res := resourceWallarmTrigger()
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]any{
+		"template_id": "bruteforce_started",
+		"actions": []any{
+			map[string]any{"action_id": "send_notification", "integration_id": []any{5, 6}},
+			map[string]any{"action_id": "block_ips", "lock_time": 2, "lock_time_format": "Hours"},
+		},
+		"threshold": []any{map[string]any{"count": 30, "period": 60, "time_format": "Seconds", "operator": "gt"}},
+	})
+	d.MarkNewResource()
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("Create: %v", diags)
+	}
+	if d.Get("actions.1.lock_time") != 2 || d.Get("actions.1.lock_time_format") != "Hours" {
+		t.Errorf("read after create: %v", d.Get("actions"))
+	}
+
+	imported := res.Data(nil)
+	imported.SetId(d.Id())
+	states, err := res.Importer.StateContext(ctx, imported, meta)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if diags := res.ReadContext(ctx, states[0], meta); diags.HasError() {
+		t.Fatalf("Read: %v", diags)
+	}
+	want := []any{
+		map[string]any{"action_id": "send_notification", "integration_id": []any{5, 6}, "lock_time": 0, "lock_time_format": ""},
+		map[string]any{"action_id": "block_ips", "integration_id": []any{}, "lock_time": 7200, "lock_time_format": "Seconds"},
+	}
+	if got := states[0].Get("actions"); !reflect.DeepEqual(got, want) {
+		t.Errorf("imported actions:\n got %v\nwant %v", got, want)
+	}
+}
@@ -0,0 +1,31 @@
+package wallarm
+
+import "sync"
+
+// runStats holds the end-of-run reporters of the provider instances
+// configured in this process.
+var runStats struct {
+	mu        sync.Mutex
+	reporters []func()
+}
+
+// registerRunStats adds a reporter for LogRunStats.
+func registerRunStats(report func()) {
+	runStats.mu.Lock()
+	defer runStats.mu.Unlock()
+	runStats.reporters = append(runStats.reporters, report)
+}
+
+// LogRunStats logs the statistics of every provider instance configured in
+// this process and drops their reporters, so each is logged once. main calls
+// it once the plugin server has shut down, i.e. at the end of the Terraform
+// run.
+func LogRunStats() {
+	runStats.mu.Lock()
+	reporters := runStats.reporters
+	runStats.reporters = nil
+	runStats.mu.Unlock()
+	for _, report := range reporters {
+		report()
+	}
+}
@@ -98,6 +98,22 @@ var (
 		},
 	}
 
+	// onConflictSchema is the on_conflict attribute of rule resources whose
+	// Create checks for an existing rule on the same action scope
+	// (guardExistingHint). It only matters on Create, so changes to an
+	// existing resource are suppressed rather than planned as updates.
+	onConflictSchema = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.StringInSlice(onConflictValues, false),
+		DiffSuppressFunc: func(_, _, _ string, d *schema.ResourceData) bool {
+			return d.Id() != ""
+		},
+		Description: "What to do when a rule of the same type already exists on the action scope at create time. " +
+			"One of: error (fail and ask for an import), adopt (take over the existing rule and update it), " +
+			"replace (delete it and create a new one). Defaults to the provider's on_conflict.",
+	}
+
 	// counterFieldOverrides makes the user-mutable common fields read-only
 	// for counter resources (bola_counter, bruteforce_counter, dirbust_counter).
 	// Counters have no UpdateContext (state-only Delete, no Update path), so
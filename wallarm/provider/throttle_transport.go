@@ -0,0 +1,228 @@
+package wallarm
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// throttleDefaultRetryAfter is the pause after a 429 without a usable
+	// Retry-After header; throttleMaxRetryAfter caps the header.
+	throttleDefaultRetryAfter = time.Second
+	throttleMaxRetryAfter     = time.Minute
+)
+
+// ThrottleStats provides a snapshot of throttleTransport's activity.
+type ThrottleStats struct {
+	Requests       int64         `json:"requests"`
+	Throttled      int64         `json:"throttled"` // HTTP 429 responses
+	RateLimitWaits int64         `json:"rate_limit_waits"`
+	InFlightWaits  int64         `json:"in_flight_waits"`
+	WaitTime       time.Duration `json:"wait_time"`
+	MaxInFlight    int64         `json:"max_in_flight"`
+}
+
+// throttleTransport is an http.RoundTripper that keeps the provider under the
+// API's rate limits: a token bucket caps requests per second, a semaphore caps
+// requests in flight, and an HTTP 429 pauses every request for its
+// Retry-After. The 429 is returned once the pause is over, so wallarm-go's
+// retry policy stays the only retry loop and its retry is not rejected again
+// right away.
+//
+// A zero rps or maxInFlight disables that limit; Retry-After is always
+// honoured.
+type throttleTransport struct {
+	transport http.RoundTripper
+	bucket    *tokenBucket
+	slots     chan struct{}
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+
+	requests       atomic.Int64
+	throttled      atomic.Int64
+	rateLimitWaits atomic.Int64
+	inFlightWaits  atomic.Int64
+	waitTime       atomic.Int64 // nanoseconds
+	inFlight       atomic.Int64
+	maxInFlight    atomic.Int64
+}
+
+func newThrottleTransport(transport http.RoundTripper, rps float64, maxInFlight int) *throttleTransport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	t := &throttleTransport{transport: transport}
+	if rps > 0 {
+		t.bucket = newTokenBucket(rps)
+	}
+	if maxInFlight > 0 {
+		t.slots = make(chan struct{}, maxInFlight)
+	}
+	return t
+}
+
+func (t *throttleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if t.slots != nil {
+		if err := t.acquireSlot(ctx); err != nil {
+			return nil, err
+		}
+		defer func() { <-t.slots }()
+	}
+	n := t.inFlight.Add(1)
+	defer t.inFlight.Add(-1)
+	for {
+		peak := t.maxInFlight.Load()
+		if n <= peak || t.maxInFlight.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+
+	if err := t.wait(ctx); err != nil {
+		return nil, err
+	}
+	t.requests.Add(1)
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+
+	t.throttled.Add(1)
+	delay := retryAfter(resp.Header.Get("Retry-After"), time.Now())
+	t.pause(delay)
+	log.Printf("[DEBUG] Wallarm API throttled %s %s, pausing requests for %s", req.Method, req.URL.Path, delay)
+	if err := t.sleep(ctx, delay); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (t *throttleTransport) acquireSlot(ctx context.Context) error {
+	select {
+	case t.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	t.inFlightWaits.Add(1)
+	start := time.Now()
+	defer func() { t.waitTime.Add(int64(time.Since(start))) }()
+	select {
+	case t.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// wait blocks until a Retry-After pause is over and the token bucket admits
+// the request.
+func (t *throttleTransport) wait(ctx context.Context) error {
+	t.mu.Lock()
+	pause := time.Until(t.pausedUntil)
+	t.mu.Unlock()
+	if err := t.sleep(ctx, pause); err != nil {
+		return err
+	}
+	if t.bucket == nil {
+		return nil
+	}
+	d := t.bucket.reserve(time.Now())
+	if d > 0 {
+		t.rateLimitWaits.Add(1)
+	}
+	return t.sleep(ctx, d)
+}
+
+func (t *throttleTransport) sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t.waitTime.Add(int64(d))
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pause holds every request back for d.
+func (t *throttleTransport) pause(d time.Duration) {
+	until := time.Now().Add(d)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if until.After(t.pausedUntil) {
+		t.pausedUntil = until
+	}
+}
+
+// Stats returns a snapshot of the transport's statistics.
+func (t *throttleTransport) Stats() ThrottleStats {
+	return ThrottleStats{
+		Requests:       t.requests.Load(),
+		Throttled:      t.throttled.Load(),
+		RateLimitWaits: t.rateLimitWaits.Load(),
+		InFlightWaits:  t.inFlightWaits.Load(),
+		WaitTime:       time.Duration(t.waitTime.Load()),
+		MaxInFlight:    t.maxInFlight.Load(),
+	}
+}
+
+// LogStats logs a summary of the transport's statistics.
+func (t *throttleTransport) LogStats() {
+	s := t.Stats()
+	log.Printf("[INFO] Wallarm API throttling stats: %d requests | %d throttled (429) | %d rate limit waits | %d in-flight waits | %s waited | %d max in flight",
+		s.Requests, s.Throttled, s.RateLimitWaits, s.InFlightWaits, s.WaitTime.Round(time.Millisecond), s.MaxInFlight)
+}
+
+// retryAfter parses a Retry-After header, in seconds or as an HTTP date.
+func retryAfter(header string, now time.Time) time.Duration {
+	d := throttleDefaultRetryAfter
+	if secs, err := strconv.Atoi(header); err == nil {
+		d = time.Duration(secs) * time.Second
+	} else if at, err := http.ParseTime(header); err == nil {
+		d = at.Sub(now)
+	}
+	return min(max(d, 0), throttleMaxRetryAfter)
+}
+
+// tokenBucket admits rps requests per second on average, with bursts of up to
+// one second's worth.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rps    float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rps float64) *tokenBucket {
+	burst := math.Max(1, math.Ceil(rps))
+	return &tokenBucket{rps: rps, burst: burst, tokens: burst}
+}
+
+// reserve takes a token and returns how long to wait until it is available.
+// Tokens may go negative, so concurrent callers queue up behind each other.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.last.IsZero() {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rps)
+	}
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rps * float64(time.Second))
+}
@@ -0,0 +1,144 @@
+package wallarm
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for header, want := range map[string]time.Duration{
+		"":                              throttleDefaultRetryAfter,
+		"garbage":                       throttleDefaultRetryAfter,
+		"0":                             0,
+		"7":                             7 * time.Second,
+		"3600":                          throttleMaxRetryAfter,
+		"-5":                            0,
+		"Thu, 01 Jan 2026 12:00:30 GMT": 30 * time.Second,
+	} {
+		if got := retryAfter(header, now); got != want {
+			t.Errorf("retryAfter(%q) = %s, want %s", header, got, want)
+		}
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	b := newTokenBucket(2)
+	now := time.Now()
+	for i := range 2 {
+		if d := b.reserve(now); d != 0 {
+			t.Fatalf("reserve %d within burst waited %s", i, d)
+		}
+	}
+	if d := b.reserve(now); d != 500*time.Millisecond {
+		t.Errorf("third reserve = %s, want 500ms", d)
+	}
+	// The queued caller's token is paid back after one second.
+	if d := b.reserve(now.Add(time.Second)); d != 0 {
+		t.Errorf("reserve after refill = %s, want 0", d)
+	}
+}
+
+func TestThrottleTransport_Returns429AfterRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	throttle := newThrottleTransport(srv.Client().Transport, 0, 0)
+	client := &http.Client{Transport: throttle}
+	start := time.Now()
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	// The 429 goes back to wallarm-go's retry policy, once the pause is over.
+	if resp.StatusCode != http.StatusTooManyRequests || calls.Load() != 1 {
+		t.Errorf("status = %d after %d requests, want the 429 after 1", resp.StatusCode, calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("returned after %s, want the 1s Retry-After honoured", elapsed)
+	}
+	if s := throttle.Stats(); s.Requests != 1 || s.Throttled != 1 {
+		t.Errorf("unexpected stats: %+v", s)
+	}
+}
+
+// recordingTransport answers the first request with firstStatus and the
+// rest with 200, recording the requests it was given.
+type recordingTransport struct {
+	firstStatus int
+	reqs        []*http.Request
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	_, _ = io.ReadAll(req.Body)
+	r.reqs = append(r.reqs, req)
+	status := http.StatusOK
+	if len(r.reqs) == 1 {
+		status = r.firstStatus
+	}
+	return &http.Response{StatusCode: status, Header: http.Header{"Retry-After": {"0"}}, Body: http.NoBody, Request: req}, nil
+}
+
+func TestLogRunStats_LogsOnce(t *testing.T) {
+	var calls int
+	registerRunStats(func() { calls++ })
+	LogRunStats()
+	LogRunStats()
+	if calls != 1 {
+		t.Errorf("reporter called %d times, want 1", calls)
+	}
+}
+
+func TestThrottleTransport_MaxInFlight(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	throttle := newThrottleTransport(srv.Client().Transport, 0, 2)
+	client := &http.Client{Transport: throttle}
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(srv.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > 2 {
+		t.Errorf("server saw %d concurrent requests, want at most 2", got)
+	}
+	if s := throttle.Stats(); s.MaxInFlight > 2 || s.InFlightWaits == 0 || s.Requests != 8 {
+		t.Errorf("unexpected stats: %+v", s)
+	}
+}
@@ -0,0 +1,297 @@
+package wallarm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	wallarm "github.com/wallarm/wallarm-go"
+)
+
+const (
+	// tokenRefreshWindow is how long before its expires_at a token from
+	// api_token_command is replaced. Tokens that live less than twice the
+	// window are replaced at half their lifetime instead.
+	tokenRefreshWindow = 5 * time.Minute
+
+	// tokenCommandTimeout bounds one run of api_token_command.
+	tokenCommandTimeout = 30 * time.Second
+
+	headerAPIToken  = "X-WallarmAPI-Token"
+	headerAPIUUID   = "X-WallarmAPI-UUID"
+	headerAPISecret = "X-WallarmAPI-Secret"
+)
+
+// tokenSource produces the auth headers of API requests. authTransport asks
+// for them on every request, so a source may rotate credentials at any time.
+type tokenSource interface {
+	// authHeaders returns the headers to authenticate the next request with.
+	authHeaders(ctx context.Context) (http.Header, error)
+	// invalidate is called when the API rejected the given headers with
+	// HTTP 401. It reports whether authHeaders may now return different
+	// credentials, i.e. whether retrying the request is worthwhile.
+	invalidate(ctx context.Context, rejected http.Header) bool
+}
+
+// tokenSourceFromConfig picks the provider's credentials: api_token_command,
+// then api_token_file, then api_token, then api_uuid/api_secret.
+func tokenSourceFromConfig(d *schema.ResourceData, apiHost string) (tokenSource, error) {
+	if v, ok := d.GetOk("api_token_command"); ok {
+		argv := make([]string, 0, len(v.([]any)))
+		for _, arg := range v.([]any) {
+			s, _ := arg.(string)
+			argv = append(argv, s)
+		}
+		if argv[0] == "" {
+			return nil, errors.New("api_token_command: the command must not be empty")
+		}
+		return newCommandTokenSource(argv, apiHost), nil
+	}
+	if v, ok := d.GetOk("api_token_file"); ok {
+		return &fileTokenSource{path: v.(string)}, nil
+	}
+	headers := make(http.Header)
+	if v, ok := d.GetOk("api_token"); ok {
+		headers.Set(headerAPIToken, v.(string))
+		return &staticTokenSource{headers: headers}, nil
+	}
+	if v, ok := d.GetOk("api_uuid"); ok {
+		headers.Set(headerAPIUUID, v.(string))
+	} else {
+		return nil, fmt.Errorf("api_uuid is required when api_token, api_token_file and api_token_command are not set: %w", wallarm.ErrInvalidCredentials)
+	}
+	if v, ok := d.GetOk("api_secret"); ok {
+		headers.Set(headerAPISecret, v.(string))
+	} else {
+		return nil, fmt.Errorf("api_secret is required when api_token, api_token_file and api_token_command are not set: %w", wallarm.ErrInvalidCredentials)
+	}
+	return &staticTokenSource{headers: headers}, nil
+}
+
+// staticTokenSource holds api_token or the deprecated api_uuid/api_secret pair.
+type staticTokenSource struct {
+	headers http.Header
+}
+
+func (s *staticTokenSource) authHeaders(context.Context) (http.Header, error) {
+	return s.headers, nil
+}
+
+func (s *staticTokenSource) invalidate(context.Context, http.Header) bool {
+	return false
+}
+
+// fileTokenSource reads the token from api_token_file, e.g. a secret mounted
+// by the CI runner. The file is read once and again after every 401, so a
+// rotated token is picked up without restarting Terraform.
+type fileTokenSource struct {
+	path string
+
+	mu    sync.Mutex
+	token string
+}
+
+func (s *fileTokenSource) authHeaders(context.Context) (http.Header, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == "" {
+		if err := s.read(); err != nil {
+			return nil, err
+		}
+	}
+	return tokenHeaders(s.token), nil
+}
+
+func (s *fileTokenSource) invalidate(_ context.Context, rejected http.Header) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.read(); err != nil {
+		log.Printf("[WARN] Wallarm API token file: %s", err)
+		return false
+	}
+	return s.token != rejected.Get(headerAPIToken)
+}
+
+func (s *fileTokenSource) read() error {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("reading api_token_file: %w", err)
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return fmt.Errorf("api_token_file %s is empty", s.path)
+	}
+	s.token = token
+	return nil
+}
+
+// tokenCommandOutput is what api_token_command prints on stdout.
+type tokenCommandOutput struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at"` // RFC3339; empty for no expiry
+}
+
+// commandTokenSource runs api_token_command, in the style of kubectl exec
+// credential plugins and AWS credential_process, and caches the token it
+// prints until shortly before its expiry or until the API rejects it.
+type commandTokenSource struct {
+	argv    []string
+	apiHost string
+	now     func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time // zero: no expiry
+}
+
+func newCommandTokenSource(argv []string, apiHost string) *commandTokenSource {
+	return &commandTokenSource{argv: argv, apiHost: apiHost, now: time.Now}
+}
+
+func (s *commandTokenSource) authHeaders(ctx context.Context) (http.Header, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == "" || !s.refreshAt.IsZero() && !s.now().Before(s.refreshAt) {
+		if err := s.run(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return tokenHeaders(s.token), nil
+}
+
+func (s *commandTokenSource) invalidate(_ context.Context, rejected http.Header) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// A concurrent request may have refreshed the token already.
+	if s.token == rejected.Get(headerAPIToken) {
+		s.token = ""
+	}
+	return true
+}
+
+func (s *commandTokenSource) run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, tokenCommandTimeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.argv[0], s.argv[1:]...)
+	cmd.Env = append(os.Environ(), "WALLARM_API_HOST="+s.apiHost)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return fmt.Errorf("running api_token_command: %w", err)
+	}
+
+	var out tokenCommandOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return fmt.Errorf("api_token_command must print {\"token\": ..., \"expires_at\": ...}: %w", err)
+	}
+	if out.Token == "" {
+		return errors.New("api_token_command printed an empty token")
+	}
+	now := s.now()
+	var refreshAt time.Time
+	if out.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, out.ExpiresAt)
+		if err != nil {
+			return fmt.Errorf("api_token_command expires_at: %w", err)
+		}
+		if !expiresAt.After(now) {
+			return fmt.Errorf("api_token_command returned a token that expired at %s", out.ExpiresAt)
+		}
+		refreshAt = expiresAt.Add(-min(tokenRefreshWindow, expiresAt.Sub(now)/2))
+		log.Printf("[DEBUG] Wallarm API token from api_token_command expires at %s, refreshing at %s",
+			expiresAt.Format(time.RFC3339), refreshAt.Format(time.RFC3339))
+	}
+	s.token, s.refreshAt = out.Token, refreshAt
+	return nil
+}
+
+func tokenHeaders(token string) http.Header {
+	h := make(http.Header)
+	h.Set(headerAPIToken, token)
+	return h
+}
+
+// authTransport is an http.RoundTripper that sets the auth headers of every
+// request from a tokenSource and, when the API answers 401 and the source
+// has other credentials to offer, retries the request once with them.
+type authTransport struct {
+	transport http.RoundTripper
+	source    tokenSource
+}
+
+func newAuthTransport(transport http.RoundTripper, source tokenSource) *authTransport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &authTransport{transport: transport, source: source}
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	headers, err := t.source.authHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.transport.RoundTrip(withAuthHeaders(req, headers))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if !t.source.invalidate(ctx, headers) {
+		return resp, nil
+	}
+	retryReq, ok := rewindRequest(req)
+	if !ok {
+		return resp, nil
+	}
+	retry, err := t.source.authHeaders(ctx)
+	if err != nil {
+		log.Printf("[WARN] Wallarm API credentials could not be refreshed after HTTP 401: %s", err)
+		return resp, nil
+	}
+	log.Printf("[DEBUG] Wallarm API rejected the credentials for %s %s, retrying with refreshed ones",
+		req.Method, req.URL.Path)
+	resp.Body.Close()
+	return t.transport.RoundTrip(withAuthHeaders(retryReq, retry))
+}
+
+// withAuthHeaders returns a shallow copy of req carrying headers; a
+// RoundTripper must not modify the request it was given.
+func withAuthHeaders(req *http.Request, headers http.Header) *http.Request {
+	r := req.Clone(req.Context())
+	for k, v := range headers {
+		r.Header[k] = v
+	}
+	return r
+}
+
+// rewindRequest returns a copy of req with a fresh body for a retry, leaving
+// req itself untouched as a RoundTripper must. It reports false if the body
+// cannot be replayed.
+func rewindRequest(req *http.Request) (*http.Request, bool) {
+	r := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return r, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	r.Body = body
+	return r, true
+}
@@ -0,0 +1,246 @@
+package wallarm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// newTokenServer accepts only requests carrying *valid as their token.
+func newTokenServer(t *testing.T, valid *atomic.Value) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get(headerAPIToken) != valid.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestAuthTransport_FileRereadOn401(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var valid atomic.Value
+	valid.Store("old")
+	srv, calls := newTokenServer(t, &valid)
+	client := &http.Client{Transport: newAuthTransport(nil, &fileTokenSource{path: path})}
+
+	get := func() int {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{}`))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if got := get(); got != http.StatusOK {
+		t.Fatalf("status = %d, want 200", got)
+	}
+
+	// The token is rotated on disk and server side.
+	valid.Store("new")
+	if err := os.WriteFile(path, []byte("new"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	calls.Store(0)
+	if got := get(); got != http.StatusOK || calls.Load() != 2 {
+		t.Errorf("after rotation: status %d in %d calls, want 200 in 2", got, calls.Load())
+	}
+
+	// An unchanged file is not worth a retry.
+	valid.Store("newer")
+	calls.Store(0)
+	if got := get(); got != http.StatusUnauthorized || calls.Load() != 1 {
+		t.Errorf("stale file: status %d in %d calls, want 401 in 1", got, calls.Load())
+	}
+}
+
+func TestAuthTransport_StaticNoRetry(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("good")
+	srv, calls := newTokenServer(t, &valid)
+	client := &http.Client{Transport: newAuthTransport(nil, &staticTokenSource{headers: tokenHeaders("bad")})}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || calls.Load() != 1 {
+		t.Errorf("status %d in %d calls, want 401 in 1", resp.StatusCode, calls.Load())
+	}
+}
+
+// tokenHelper writes a credential helper printing tok-<n> with the given
+// expiry, n counting its runs.
+func tokenHelper(t *testing.T, expiresAt string) []string {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	count := filepath.Join(t.TempDir(), "count")
+	script := `n=$(cat "$1" 2>/dev/null || echo 0); n=$((n+1)); echo $n > "$1"; ` +
+		`printf '{"token":"tok-%s","expires_at":"` + expiresAt + `"}' $n`
+	return []string{"sh", "-c", script, "helper", count}
+}
+
+// rotatingTokenSource hands out a new token after every invalidation.
+type rotatingTokenSource struct {
+	n int
+}
+
+func (s *rotatingTokenSource) authHeaders(context.Context) (http.Header, error) {
+	return http.Header{headerAPIToken: {fmt.Sprint("token-", s.n)}}, nil
+}
+
+func (s *rotatingTokenSource) invalidate(context.Context, http.Header) bool {
+	s.n++
+	return true
+}
+
+func TestAuthTransport_RetryLeavesRequestUntouched(t *testing.T) {
+	rec := &recordingTransport{firstStatus: http.StatusUnauthorized}
+	req := httptest.NewRequest(http.MethodPost, "https://api.example.com/v1/objects/hint/create", strings.NewReader(`{}`))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader(`{}`)), nil }
+	body := req.Body
+
+	resp, err := newAuthTransport(rec, &rotatingTokenSource{}).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || len(rec.reqs) != 2 {
+		t.Fatalf("status = %d after %d requests, want 200 after 2", resp.StatusCode, len(rec.reqs))
+	}
+	if got := rec.reqs[1].Header[headerAPIToken]; len(got) != 1 || got[0] != "token-1" {
+		t.Errorf("retry token = %v, want token-1", got)
+	}
+	if _, ok := req.Header[headerAPIToken]; ok || req.Body != body {
+		t.Error("the retry modified the caller's request")
+	}
+}
+
+func TestCommandTokenSource_RefreshBeforeExpiry(t *testing.T) {
+	s := newCommandTokenSource(tokenHelper(t, "2030-01-01T00:00:00Z"), "https://api.wallarm.com")
+	now := time.Date(2029, 12, 31, 23, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	token := func() string {
+		t.Helper()
+		h, err := s.authHeaders(ctx)
+		if err != nil {
+			t.Fatalf("authHeaders: %v", err)
+		}
+		return h.Get(headerAPIToken)
+	}
+	if got := token(); got != "tok-1" {
+		t.Fatalf("token = %q, want tok-1", got)
+	}
+	now = now.Add(50 * time.Minute) // 10 minutes before expiry
+	if got := token(); got != "tok-1" {
+		t.Errorf("token = %q, want the cached tok-1", got)
+	}
+	now = now.Add(6 * time.Minute) // inside the refresh window
+	if got := token(); got != "tok-2" {
+		t.Errorf("token = %q, want tok-2", got)
+	}
+
+	// A 401 for a token already replaced does not discard the new one.
+	if !s.invalidate(ctx, tokenHeaders("tok-1")) || token() != "tok-2" {
+		t.Error("stale 401 discarded the current token")
+	}
+	if !s.invalidate(ctx, tokenHeaders("tok-2")) || token() != "tok-3" {
+		t.Error("401 did not make the helper run again")
+	}
+}
+
+func TestCommandTokenSource_Errors(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	cases := map[string]struct {
+		script string
+		want   string
+	}{
+		"exit":    {`echo "not logged in" >&2; exit 3`, "not logged in"},
+		"garbage": {`echo token`, "must print"},
+		"empty":   {`echo '{"token":""}'`, "empty token"},
+		"expired": {`echo '{"token":"t","expires_at":"2000-01-01T00:00:00Z"}'`, "expired"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := newCommandTokenSource([]string{"sh", "-c", tc.script}, "")
+			_, err := s.authHeaders(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestProviderConfigure_TokenFile(t *testing.T) {
+	srv := testFakeAPIServer(t)
+	path := filepath.Join(t.TempDir(), "token")
+	configure := func() diag.Diagnostics {
+		p := Provider()
+		d := schema.TestResourceDataRaw(t, p.Schema, map[string]any{
+			"api_host":       srv.URL,
+			"allow_http":     true,
+			"api_token_file": path,
+			"retries":        0,
+		})
+		_, diags := ProviderConfigure(context.Background(), d, p)
+		return diags
+	}
+	if diags := configure(); !diags.HasError() || !strings.Contains(diags[0].Summary, "api_token_file") {
+		t.Errorf("missing file: diags = %v", diags)
+	}
+	if err := os.WriteFile(path, []byte(testAccReplayToken), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if diags := configure(); diags.HasError() {
+		t.Errorf("ProviderConfigure: %v", diags)
+	}
+	if got := srv.Requests(); len(got) != 1 || got[0] != "POST /v1/user" {
+		t.Errorf("requests = %v, want the user details lookup", got)
+	}
+}
+
+func TestProviderTokenSources_EnvTokenWithFile(t *testing.T) {
+	t.Setenv("WALLARM_API_TOKEN", strings.Repeat("a", 64))
+	path := filepath.Join(t.TempDir(), "token")
+	raw := map[string]any{"api_token_file": path}
+
+	if diags := Provider().Validate(terraform.NewResourceConfigRaw(raw)); diags.HasError() {
+		t.Fatalf("Validate: %v", diags)
+	}
+	d := schema.TestResourceDataRaw(t, Provider().Schema, raw)
+	src, err := tokenSourceFromConfig(d, "https://api.wallarm.com")
+	if err != nil {
+		t.Fatalf("tokenSourceFromConfig: %v", err)
+	}
+	if f, ok := src.(*fileTokenSource); !ok || f.path != path {
+		t.Errorf("token source = %#v, want api_token_file over WALLARM_API_TOKEN", src)
+	}
+}
@@ -0,0 +1,159 @@
+package wallarm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// triggerThreshold tells whether a template requires, rejects or merely accepts
+// a threshold block.
+type triggerThreshold int
+
+const (
+	triggerThresholdOptional triggerThreshold = iota
+	triggerThresholdRequired
+	triggerThresholdRejected
+)
+
+// triggerTemplate describes the documented constraints of one trigger
+// template. See references/triggers.md for the source of each row.
+type triggerTemplate struct {
+	// Actions lists the accepted action_id values; nil accepts every action.
+	Actions []string
+	// Threshold tells whether a threshold block is required or rejected.
+	Threshold triggerThreshold
+}
+
+// Trigger actions that take lock_time / lock_time_format.
+var triggerLockActions = []string{"block_ips", "add_to_graylist"}
+
+// triggerOperators lists the documented threshold operators.
+var triggerOperators = []string{"gt"}
+
+// triggerTemplates is the template -> actions/threshold matrix enforced at plan
+// time by triggerTemplateCustomizeDiff. Templates without a documented
+// constraint keep the zero value, which accepts everything.
+var triggerTemplates = map[string]triggerTemplate{
+	"user_created":            {Actions: []string{"send_notification"}, Threshold: triggerThresholdRejected},
+	"attacks_exceeded":        {Threshold: triggerThresholdRequired},
+	"hits_exceeded":           {Threshold: triggerThresholdRequired},
+	"incidents_exceeded":      {Threshold: triggerThresholdRequired},
+	"vector_attack":           {Threshold: triggerThresholdRequired},
+	"bruteforce_started":      {Threshold: triggerThresholdRequired},
+	"forced_browsing_started": {},
+	"bola_search_started":     {},
+	"blacklist_ip_added":      {},
+	"api_structure_changed":   {},
+	"attack_ip_grouping":      {},
+	"compromised_logins":      {},
+	"rogue_api_detected":      {},
+}
+
+// triggerFilterTemplates lists the templates accepting a filter that is only
+// documented for some of them. Filters not listed are accepted by every
+// template.
+var triggerFilterTemplates = map[string][]string{
+	"change_type":    {"api_structure_changed"},
+	"deviation_type": {"rogue_api_detected"},
+	"api_spec_ids":   {"rogue_api_detected"},
+}
+
+// acceptsAction reports whether the template accepts the action.
+func (t triggerTemplate) acceptsAction(actionID string) bool {
+	return t.Actions == nil || slices.Contains(t.Actions, actionID)
+}
+
+// triggerTemplateIDs returns the known template IDs in sorted order.
+func triggerTemplateIDs() []string {
+	ids := make([]string, 0, len(triggerTemplates))
+	for id := range triggerTemplates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func triggerAllowedList(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
+
+// triggerTemplateCustomizeDiff validates filters, actions and threshold against
+// the template constraints so invalid combinations fail at plan instead of at the
+// API.
+func triggerTemplateCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	if !d.NewValueKnown("template_id") {
+		return nil
+	}
+	templateID := d.Get("template_id").(string)
+	tpl, ok := triggerTemplates[templateID]
+	if !ok {
+		return nil
+	}
+	return validateTriggerConfig(templateID, tpl,
+		d.Get("filters").([]any), d.Get("actions").([]any), d.Get("threshold").([]any))
+}
+
+// validateTriggerConfig checks the threshold, filters and actions of one trigger
+// against its template; the threshold check runs first.
+func validateTriggerConfig(templateID string, tpl triggerTemplate, filters, actions, threshold []any) error {
+	hasThreshold := len(threshold) > 0 && threshold[0] != nil
+	switch {
+	case tpl.Threshold == triggerThresholdRequired && !hasThreshold:
+		return fmt.Errorf(`"threshold" must be presented with the "%s" template`, templateID)
+	case tpl.Threshold == triggerThresholdRejected && hasThreshold:
+		return fmt.Errorf(`"threshold" is not supported by the "%s" template`, templateID)
+	case hasThreshold:
+		m := threshold[0].(map[string]any)
+		if operator, _ := m["operator"].(string); operator != "" && !slices.Contains(triggerOperators, operator) {
+			return fmt.Errorf("threshold: operator %q is not supported, allowed operators: %s",
+				operator, triggerAllowedList(triggerOperators))
+		}
+	}
+
+	for i, f := range filters {
+		m, ok := f.(map[string]any)
+		if !ok {
+			continue
+		}
+		filterID, _ := m["filter_id"].(string)
+		if templates, scoped := triggerFilterTemplates[filterID]; scoped && !slices.Contains(templates, templateID) {
+			return fmt.Errorf("filters.%d: filter %q is not supported by the %q template, allowed templates: %s",
+				i, filterID, templateID, triggerAllowedList(templates))
+		}
+	}
+
+	for i, a := range actions {
+		m, ok := a.(map[string]any)
+		if !ok {
+			continue
+		}
+		actionID, _ := m["action_id"].(string)
+		if !tpl.acceptsAction(actionID) {
+			return fmt.Errorf("actions.%d: action %q is not supported by the %q template, allowed actions: %s",
+				i, actionID, templateID, triggerAllowedList(tpl.Actions))
+		}
+		lockTime, _ := m["lock_time"].(int)
+		lockTimeFormat, _ := m["lock_time_format"].(string)
+		if !slices.Contains(triggerLockActions, actionID) {
+			if lockTime != 0 || lockTimeFormat != "" {
+				return fmt.Errorf("actions.%d: lock_time and lock_time_format are only supported by the %s actions, got %q",
+					i, strings.Join(triggerLockActions, " and "), actionID)
+			}
+		} else if lockTime != 0 && lockTimeFormat == "" {
+			return fmt.Errorf("actions.%d: lock_time_format must be set together with lock_time for %q", i, actionID)
+		}
+		if ids, _ := m["integration_id"].([]any); len(ids) > 0 && actionID != "send_notification" {
+			return fmt.Errorf("actions.%d: integration_id is only supported by the send_notification action, got %q", i, actionID)
+		}
+	}
+
+	return nil
+}